@@ -2,13 +2,32 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"io"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	// Embed the timezone database (~450 KB) so Europe/London resolves on
+	// images without tzdata, such as alpine and scratch.
+	_ "time/tzdata"
 
 	"github.com/joho/godotenv"
+	"go.opentelemetry.io/otel/trace"
+	// Pure-Go SQLite driver for HISTORY_DB, registered as "sqlite".
+	_ "modernc.org/sqlite"
 
 	"github.com/emanuelefumagalli/test-agent/internal/agent"
+	"github.com/emanuelefumagalli/test-agent/internal/httpx"
 	"github.com/emanuelefumagalli/test-agent/internal/ollama"
+	"github.com/emanuelefumagalli/test-agent/internal/tracing"
 	"github.com/emanuelefumagalli/test-agent/internal/weather"
 )
 
@@ -23,44 +42,515 @@ const (
 )
 
 func main() {
+	airport := flag.String("airport", "", "IATA or ICAO code of the wind check's UK airport, e.g. LHR (default $WIND_AIRPORT)")
+	locationsFile := flag.String("locations-file", "", "YAML file overriding the locations, reloaded when it changes (default $LOCATIONS_FILE)")
+	testNotify := flag.Bool("test-notify", false, "send a test message to every configured notifier and exit")
+	flag.Parse()
 	_ = godotenv.Load()
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	userAgent := envOrDefault("USER_AGENT", httpx.DefaultUserAgent)
+	httpClient := httpx.NewClient(10*time.Second, userAgent)
+	// One limiter for every Open-Meteo request, well inside the free tier.
+	limiter := weather.NewRateLimiter(envInt("OPEN_METEO_RPM", 60))
+	weatherTimeout := envDuration("OPEN_METEO_TIMEOUT", 15*time.Second)
+	weatherMaxAge := envDuration("OPEN_METEO_MAX_AGE", time.Hour)
+	weatherNoCache := envBool("OPEN_METEO_NO_CACHE")
+	// Raw Open-Meteo responses, for diagnosing odd forecasts. Off by default:
+	// a 16-day response is tens of KB.
+	var rawDebug io.Writer
+	if path := os.Getenv("OPEN_METEO_DEBUG_FILE"); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			log.Fatalf("OPEN_METEO_DEBUG_FILE: %v", err)
+		}
+		defer f.Close()
+		rawDebug = f
+	}
+
+	windLocation := envOrDefault("WIND_LOCATION", "London Heathrow")
+	windLat := mustEnvFloat("WIND_LAT", heathrowLatitude)
+	windLon := mustEnvFloat("WIND_LON", heathrowLongitude)
+	if *airport == "" {
+		*airport = os.Getenv("WIND_AIRPORT")
+	}
+	if code := *airport; code != "" {
+		lat, lon, name, ok := weather.LookupAirport(code)
+		if !ok {
+			log.Fatalf("airport: unknown airport %q (known: %s)", code, strings.Join(weather.AirportCodes(), ", "))
+		}
+		windLat, windLon = lat, lon
+		windLocation = envOrDefault("WIND_LOCATION", name)
+	}
+	rainLocation := envOrDefault("RAIN_LOCATION", "Twickenham")
+	rainLat := mustEnvFloat("RAIN_LAT", twickenhamLatitude)
+	rainLon := mustEnvFloat("RAIN_LON", twickenhamLongitude)
+	var extraWind []agent.Location
+	for _, code := range strings.Split(os.Getenv("EXTRA_WIND_AIRPORTS"), ",") {
+		if code = strings.TrimSpace(code); code == "" {
+			continue
+		}
+		lat, lon, name, ok := weather.LookupAirport(code)
+		if !ok {
+			log.Fatalf("EXTRA_WIND_AIRPORTS: unknown airport %q (known: %s)", code, strings.Join(weather.AirportCodes(), ", "))
+		}
+		extraWind = append(extraWind, agent.Location{Name: name, Latitude: lat, Longitude: lon})
+	}
+	// WIND_SCHEDULE is read in UTC like WIND_HOUR, RAIN_SCHEDULE in London time.
+	var windSchedule, rainSchedule agent.Scheduler
+	if spec := os.Getenv("WIND_SCHEDULE"); spec != "" {
+		s, err := agent.ParseSchedule(spec, time.UTC)
+		if err != nil {
+			log.Fatalf("WIND_SCHEDULE: %v", err)
+		}
+		windSchedule = s
+	}
+	if spec := os.Getenv("RAIN_SCHEDULE"); spec != "" {
+		london, _ := time.LoadLocation("Europe/London")
+		s, err := agent.ParseSchedule(spec, london)
+		if err != nil {
+			log.Fatalf("RAIN_SCHEDULE: %v", err)
+		}
+		rainSchedule = s
+	}
+	var compareWind *agent.Location
+	if code := os.Getenv("COMPARE_WIND_AIRPORT"); code != "" {
+		lat, lon, name, ok := weather.LookupAirport(code)
+		if !ok {
+			log.Fatalf("COMPARE_WIND_AIRPORT: unknown airport %q (known: %s)", code, strings.Join(weather.AirportCodes(), ", "))
+		}
+		compareWind = &agent.Location{Name: name, Latitude: lat, Longitude: lon}
+	}
+	log.Printf("wind location: %s (%.4f, %.4f)", windLocation, windLat, windLon)
+	log.Printf("rain location: %s (%.4f, %.4f)", rainLocation, rainLat, rainLon)
+
+	var sink *agent.FileSink
+	if path := os.Getenv("REPORT_LOG"); path != "" {
+		renderer, err := agent.ParseRenderer(os.Getenv("REPORT_LOG_FORMAT"))
+		if err != nil {
+			log.Fatalf("REPORT_LOG_FORMAT: %v", err)
+		}
+		sink = &agent.FileSink{Path: path, MaxBytes: int64(envInt("REPORT_LOG_MAX_BYTES", 10<<20)), Renderer: renderer}
+	}
+
+	// HISTORY_DB defaults to the SQLite driver imported above; other
+	// drivers must be linked in as well.
+	var runLog agent.RunLog
+	if dsn := os.Getenv("HISTORY_DB"); dsn != "" {
+		db, err := sql.Open(envOrDefault("HISTORY_DB_DRIVER", "sqlite"), dsn)
+		if err != nil {
+			log.Printf("warning: history database disabled: %v", err)
+		} else {
+			defer db.Close()
+			runLog = &agent.SQLRunLog{DB: db}
+		}
+	}
+
+	units, err := weather.ParseUnitSystem(os.Getenv("UNIT_SYSTEM"))
+	if err != nil {
+		log.Fatalf("UNIT_SYSTEM: %v", err)
+	}
+
+	tableColumns, err := agent.ParseTableColumns(os.Getenv("TABLE_COLUMNS"))
+	if err != nil {
+		log.Fatalf("TABLE_COLUMNS: %v", err)
+	}
+
+	var markdown *agent.MarkdownSink
+	if dir := os.Getenv("MARKDOWN_DIR"); dir != "" {
+		markdown = &agent.MarkdownSink{Dir: dir, FilenameTemplate: os.Getenv("MARKDOWN_FILENAME")}
+	}
+
+	var airQuality *weather.AirQualityClient
+	if envBool("AIR_QUALITY") {
+		airQuality = &weather.AirQualityClient{
+			Latitude:  rainLat,
+			Longitude: rainLon,
+			UserAgent: userAgent,
+			APIKey:    envSecret("OPEN_METEO_API_KEY"),
+			Limiter:   limiter,
+			Timeout:   weatherTimeout,
+			Debug:     rawDebug,
+		}
+	}
+
+	var notifiers []agent.Notifier
+	if url := envSecret("SLACK_WEBHOOK_URL"); url != "" {
+		notifiers = append(notifiers, &agent.SlackNotifier{WebhookURL: url, HTTPClient: httpClient})
+	}
+	if instance := os.Getenv("MASTODON_URL"); instance != "" {
+		notifiers = append(notifiers, &agent.MastodonNotifier{
+			InstanceURL: instance,
+			AccessToken: envSecret("MASTODON_TOKEN"),
+			Visibility:  os.Getenv("MASTODON_VISIBILITY"),
+			HTTPClient:  httpClient,
+		})
+	}
+	if url := envSecret("WEBHOOK_URL"); url != "" {
+		notifiers = append(notifiers, &agent.WebhookNotifier{URL: url, HTTPClient: httpClient})
+	}
+	if addr := os.Getenv("SMTP_ADDR"); addr != "" {
+		notifiers = append(notifiers, &agent.EmailNotifier{
+			Addr:     addr,
+			From:     os.Getenv("SMTP_FROM"),
+			To:       strings.Split(os.Getenv("SMTP_TO"), ","),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: envSecret("SMTP_PASSWORD"),
+		})
+	}
+
+	// Telegram alone may need a proxy where it's blocked.
+	var telegramProxy *url.URL
+	if raw := envSecret("TELEGRAM_PROXY"); raw != "" {
+		u, err := httpx.ParseProxyURL(raw)
+		if err != nil {
+			log.Fatalf("TELEGRAM_PROXY: %v", err)
+		}
+		telegramProxy = u
+	}
+
+	// Traces go to an OTLP/HTTP collector when the standard OTel variables
+	// name one; otherwise tracing is off.
+	var (
+		tracerProvider  trace.TracerProvider
+		shutdownTracing = func(context.Context) error { return nil }
+	)
+	if tracing.Configured() {
+		tp, err := tracing.NewProvider(ctx, httpClient)
+		if err != nil {
+			log.Fatalf("tracing: %v", err)
+		}
+		tracerProvider, shutdownTracing = tp, tp.Shutdown
+	}
+
+	theme := agent.DefaultTheme
+	// MARKERS is THEME's name from before themes covered every marker.
+	switch name := strings.ToLower(envOrDefault("THEME", os.Getenv("MARKERS"))); name {
+	case "", "emoji":
+	case "plain":
+		theme = agent.PlainTheme
+	default:
+		log.Printf("warning: unknown THEME %q, using emoji", name)
+	}
+	for key, marker := range map[string]*string{
+		"MARKER_CALM":           &theme.Calm,
+		"MARKER_MODERATE":       &theme.Moderate,
+		"MARKER_WINDY":          &theme.Windy,
+		"MARKER_SEVERE":         &theme.Severe,
+		"MARKER_DRY":            &theme.Dry,
+		"MARKER_MAYBE_RAIN":     &theme.MaybeRain,
+		"MARKER_RAIN":           &theme.Rain,
+		"MARKER_EASTERLY":       &theme.Easterly,
+		"MARKER_LIGHT_EASTERLY": &theme.LightEasterly,
+		"MARKER_WESTERLY":       &theme.Westerly,
+		"MARKER_GUST":           &theme.Gust,
+	} {
+		*marker = envOrDefault(key, *marker)
+	}
+
+	policy := agent.DefaultPolicy()
+	policy.FetchRetries = envInt("FETCH_RETRIES", policy.FetchRetries)
+	policy.OllamaRetries = envInt("OLLAMA_RETRIES", policy.OllamaRetries)
 
 	ag := agent.New(agent.Config{
 		// Wind check at 10am UTC
-		WindLocation: "London Heathrow",
+		WindLocation: windLocation,
 		WindDays:     15,
 		WindHour:     10,
+		WindSchedule: windSchedule,
 		WindWeather: &weather.OpenMeteoClient{
-			Latitude:  heathrowLatitude,
-			Longitude: heathrowLongitude,
+			Latitude:        windLat,
+			Longitude:       windLon,
+			Units:           units,
+			TemperatureUnit: os.Getenv("TEMPERATURE_UNIT"),
+			WindSpeedUnit:   os.Getenv("WIND_SPEED_UNIT"),
+			UserAgent:       userAgent,
+			APIKey:          envSecret("OPEN_METEO_API_KEY"),
+			Limiter:         limiter,
+			Timeout:         weatherTimeout,
+			Debug:           rawDebug,
+			MaxAge:          weatherMaxAge,
+			NoCache:         weatherNoCache,
+			StartOffsetDays: envInt("START_OFFSET_DAYS", 0),
 		},
+		ExtraWindLocations:  extraWind,
+		FetchConcurrency:    envInt("FETCH_CONCURRENCY", 4),
+		CompareWindLocation: compareWind,
 
 		// Rain check at 7:30am London time
-		RainLocation: "Twickenham",
+		RainLocation: rainLocation,
 		RainDays:     7,
 		RainHour:     7,
+		RainSchedule: rainSchedule,
 		RainWeather: &weather.OpenMeteoClient{
-			Latitude:  twickenhamLatitude,
-			Longitude: twickenhamLongitude,
+			Latitude:   rainLat,
+			Longitude:  rainLon,
+			Units:      units,
+			PrecipUnit: os.Getenv("PRECIP_UNIT"),
+			UserAgent:  userAgent,
+			APIKey:     envSecret("OPEN_METEO_API_KEY"),
+			Limiter:    limiter,
+			Timeout:    weatherTimeout,
+			Debug:      rawDebug,
+			MaxAge:     weatherMaxAge,
+			NoCache:    weatherNoCache,
 		},
 
+		AirQuality: airQuality,
+
 		Ollama: &ollama.Client{
 			Host:  envOrDefault("OLLAMA_HOST", "http://127.0.0.1:11434"),
 			Model: envOrDefault("OLLAMA_MODEL", "llama3.1"),
+
+			KeepAlive:     os.Getenv("OLLAMA_KEEP_ALIVE"),
+			Timeout:       envDuration("OLLAMA_TIMEOUT", 15*time.Minute),
+			UserAgent:     userAgent,
+			MaxConcurrent: envInt("OLLAMA_MAX_CONCURRENT", 1),
 		},
-		TelegramToken:  os.Getenv("TELEGRAM_TOKEN"),
-		TelegramChatID: os.Getenv("TELEGRAM_CHAT_ID"),
+		OllamaOfflineNote: envBool("OLLAMA_OFFLINE_NOTE"),
+		TelegramToken:     envSecret("TELEGRAM_TOKEN"),
+		TelegramChatID:    os.Getenv("TELEGRAM_CHAT_ID"),
+		TelegramParseMode: os.Getenv("TELEGRAM_PARSE_MODE"),
+		HTTPClient:        httpClient,
+		TelegramProxy:     telegramProxy,
+		TracerProvider:    tracerProvider,
+
+		SparklineInTelegram:     envBool("TELEGRAM_SPARKLINE"),
+		TelegramSeparateSummary: envBool("TELEGRAM_SEPARATE_SUMMARY"),
+		TelegramPinKind:         os.Getenv("TELEGRAM_PIN"),
+		Lang:                    envOrDefault("REPORT_LANG", "en"),
+		WindDecimals:            envInt("WIND_DECIMALS", 0),
+		TableSort:               os.Getenv("TABLE_SORT"),
+		CompactTable:            envBool("COMPACT_TABLE"),
+		CompactTolerance:        mustEnvFloat("COMPACT_TOLERANCE_KMH", 0),
+		GustMarkerThreshold:     mustEnvFloat("GUST_MARKER_KMH", 0),
+		ConfidenceHorizon:       envInt("CONFIDENCE_HORIZON", 7),
+		ExcludeBeyondHorizon:    envBool("EXCLUDE_BEYOND_HORIZON"),
+		DominantMargin:          envInt("DOMINANT_MARGIN_DAYS", 0),
+		MinEasterlySpeed:        mustEnvFloat("MIN_EASTERLY_KMH", 0),
+		RunwayReport:            envBool("RUNWAY_REPORT"),
+		Runways:                 agent.Runways{Easterly: os.Getenv("RUNWAYS_EASTERLY"), Westerly: os.Getenv("RUNWAYS_WESTERLY")},
+		Theme:                   theme,
+		DirectionSwingThreshold: mustEnvFloat("DIRECTION_SWING_DEG", 0),
+		StableDays:              envInt("STABLE_DAYS", 0),
+		StableTolerance:         mustEnvFloat("STABLE_TOLERANCE_KMH", 0),
+		MaxPromptDays:           envInt("MAX_PROMPT_DAYS", 10),
+		RichPrompt:              envBool("RICH_PROMPT"),
+		RelativeDates:           envBool("RELATIVE_DATES"),
+		OnlyOnWeekdays:          envBool("ONLY_ON_WEEKDAYS"),
+		QuietHours:              [2]int{envInt("QUIET_HOURS_START", 0), envInt("QUIET_HOURS_END", 0)},
+		DaytimeHours:            [2]int{envInt("DAYTIME_HOURS_START", 0), envInt("DAYTIME_HOURS_END", 0)},
+		CatchUpOnStart:          envBoolOr("CATCH_UP_ON_START", os.Getenv("STATE_FILE") != ""),
+		ScheduleJitter:          envDuration("SCHEDULE_JITTER", 0),
+		WeeklyOverview:          envBool("WEEKLY_OVERVIEW"),
+		WeekStart:               os.Getenv("WEEK_START"),
+		TransitionTimeline:      envBool("TRANSITION_TIMELINE"),
+		HourlyDirection:         envBool("HOURLY_DIRECTION"),
+		ShowTemperature:         envBool("SHOW_TEMPERATURE"),
+		TemperatureBars:         envBool("TEMPERATURE_BARS"),
+		TableColumns:            tableColumns,
+		SunWindEvent:            os.Getenv("SUN_WIND_EVENT"),
+		SunWindOffset:           envDuration("SUN_WIND_OFFSET", 0),
+		CurrentConditions:       envBool("CURRENT_CONDITIONS"),
+		IssuedFooter:            envBool("ISSUED_FOOTER"),
+		Notifiers:               notifiers,
+		FileSink:                sink,
+		MarkdownSink:            markdown,
+		WeekendComparison:       envBool("WEEKEND_COMPARISON"),
+		EasterlyChangesOnly:     envBool("EASTERLY_CHANGES_ONLY"),
+		RunLog:                  runLog,
+		AccuracyReport:          envBool("ACCURACY_REPORT"),
+		Policy:                  &policy,
+		GustAlertThreshold:      mustEnvFloat("GUST_ALERT_KMH", 0),
+		SevereGustThreshold:     mustEnvFloat("SEVERE_GUST_KMH", 0),
+		SendAllClear:            envBool("SEND_ALL_CLEAR"),
+		TelegramAlertChatID:     os.Getenv("TELEGRAM_ALERT_CHAT_ID"),
+		AlertCooldown:           envDuration("ALERT_COOLDOWN", 48*time.Hour),
+		StatePath:               os.Getenv("STATE_FILE"),
 	})
 
-	if err := ag.Run(ctx); err != nil {
+	if *testNotify {
+		if err := ag.TestNotifications(ctx); err != nil {
+			log.Fatalf("test notifications: %v", err)
+		}
+		return
+	}
+
+	if *locationsFile == "" {
+		*locationsFile = os.Getenv("LOCATIONS_FILE")
+	}
+	if path := *locationsFile; path != "" {
+		locs, err := agent.LoadLocations(path)
+		if err != nil {
+			log.Fatalf("locations file: %v", err)
+		}
+		ag.SetLocations(locs)
+		go func() {
+			if err := ag.WatchLocations(ctx, path); err != nil && ctx.Err() == nil {
+				log.Printf("warning: locations file won't be reloaded: %v", err)
+			}
+		}()
+	}
+
+	var (
+		httpSrv *http.Server
+		webhook *agent.TelegramWebhook
+	)
+	if addr := os.Getenv("HTTP_ADDR"); addr != "" {
+		token := envSecret("RUN_TOKEN")
+		secret := envSecret("TELEGRAM_WEBHOOK_SECRET")
+		if token == "" && secret == "" {
+			log.Fatalf("HTTP_ADDR needs RUN_TOKEN to protect POST /run")
+		}
+		srv := &agent.Server{Agent: ag, Token: token}
+		if secret != "" {
+			if !validWebhookSecret(secret) {
+				log.Fatalf("TELEGRAM_WEBHOOK_SECRET must be 1-256 letters, digits, _ and -")
+			}
+			var chats []string
+			for _, id := range strings.Split(os.Getenv("TELEGRAM_WEBHOOK_CHATS"), ",") {
+				if id = strings.TrimSpace(id); id != "" {
+					chats = append(chats, id)
+				}
+			}
+			if len(chats) == 0 && strings.HasPrefix(os.Getenv("TELEGRAM_CHAT_ID"), "@") {
+				log.Printf("warning: TELEGRAM_CHAT_ID is a channel username, which never matches the numeric chat IDs commands come from; list the chat's ID in TELEGRAM_WEBHOOK_CHATS to answer commands")
+			}
+			webhook = &agent.TelegramWebhook{Agent: ag, SecretToken: secret, Chats: chats}
+			srv.Webhook = webhook
+			if url := os.Getenv("TELEGRAM_WEBHOOK_URL"); url != "" {
+				if err := srv.Webhook.Register(ctx, url); err != nil {
+					log.Printf("warning: register telegram webhook: %v", err)
+				}
+			}
+		}
+		// No WriteTimeout: POST /run answers once the whole run is done.
+		httpSrv = &http.Server{
+			Addr:              addr,
+			Handler:           srv.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       30 * time.Second,
+		}
+		go func() {
+			log.Printf("listening on %s", addr)
+			if err := httpSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("http server: %v", err)
+			}
+		}()
+	}
+
+	err = ag.Run(ctx)
+	if httpSrv != nil {
+		// Let a manual run in progress finish and answer.
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := httpSrv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("warning: http server shutdown: %v", err)
+		}
+		// Then let Telegram commands still running reply.
+		if webhook != nil {
+			if err := webhook.Shutdown(shutdownCtx); err != nil {
+				log.Printf("warning: telegram commands still running at shutdown: %v", err)
+			}
+		}
+		cancel()
+	}
+	// Flush the spans of the last run before exiting.
+	flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := shutdownTracing(flushCtx); err != nil {
+		log.Printf("warning: flush traces: %v", err)
+	}
+	cancel()
+	if err != nil && !errors.Is(err, context.Canceled) {
 		log.Fatalf("agent failed: %v", err)
 	}
 }
 
+// envOrDefault returns key's value, read from key_FILE when only that is set,
+// or fallback.
 func envOrDefault(key, fallback string) string {
-	if v := os.Getenv(key); v != "" {
+	if v := envSecret(key); v != "" {
 		return v
 	}
 	return fallback
 }
+
+// envSecret returns key's value. When key is unset and key_FILE names a file,
+// as with Docker and Kubernetes secrets, the file's contents are used
+// instead, minus trailing whitespace. An unreadable file is fatal.
+func envSecret(key string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	path := os.Getenv(key + "_FILE")
+	if path == "" {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("read %s_FILE: %v", key, err)
+	}
+	return strings.TrimRight(string(data), " \t\r\n")
+}
+
+// envBool reports whether key is set to a true value (1, t, true...).
+func envBool(key string) bool {
+	return envBoolOr(key, false)
+}
+
+// envBoolOr parses key as a bool, returning fallback when unset or invalid.
+func envBoolOr(key string, fallback bool) bool {
+	v, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+// envInt parses key as an integer, returning fallback when unset or invalid.
+func envInt(key string, fallback int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("invalid %s=%q, using %d: %v", key, v, fallback, err)
+		return fallback
+	}
+	return n
+}
+
+// mustEnvFloat parses key as a float, exiting on malformed input so a typo in
+// a coordinate never silently reports the wrong place.
+func mustEnvFloat(key string, fallback float64) float64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		log.Fatalf("invalid %s=%q: %v", key, v, err)
+	}
+	return f
+}
+
+// envDuration parses key as a time.Duration, returning fallback when unset or invalid.
+func envDuration(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("invalid %s=%q, using %s: %v", key, v, fallback, err)
+		return fallback
+	}
+	return d
+}
+
+// validWebhookSecret reports whether s is a secret_token Telegram accepts:
+// 1-256 letters, digits, underscores and hyphens.
+func validWebhookSecret(s string) bool {
+	return len(s) >= 1 && len(s) <= 256 && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-") == ""
+}
@@ -0,0 +1,163 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// checkAccuracy is the state key recording the last accuracy report.
+const checkAccuracy = "accuracy"
+
+// accuracyWindow is how many past days the accuracy report scores.
+const accuracyWindow = 28
+
+// LeadAccuracy scores the forecasts made a given number of days ahead.
+type LeadAccuracy struct {
+	// LeadDays is how far ahead of the day the forecast was fetched, by UTC
+	// date: 1 for the day before.
+	LeadDays int
+	// Samples is the number of forecast days with an observation.
+	Samples int
+	// SpeedMAE is the mean absolute error of the max wind speed, km/h.
+	SpeedMAE float64
+	// DirectionSamples counts the samples where both forecast and
+	// observation had a direction, and DirectionHits those that agreed on
+	// easterly versus westerly.
+	DirectionSamples int
+	DirectionHits    int
+}
+
+// DirectionHitRate returns the share of DirectionSamples that were hits, and
+// 0 when there are none.
+func (l LeadAccuracy) DirectionHitRate() float64 {
+	if l.DirectionSamples == 0 {
+		return 0
+	}
+	return float64(l.DirectionHits) / float64(l.DirectionSamples)
+}
+
+// ForecastAccuracy compares each logged forecast day against the observed
+// day with the same date and scores them by lead time, shortest first.
+// Forecasts for days without an observation, and any made on or after the
+// day itself, are skipped. minSpeed is as for Config.MinEasterlySpeed.
+func ForecastAccuracy(forecasts []StoredForecast, observed []weather.ForecastDay, minSpeed float64) []LeadAccuracy {
+	obs := make(map[string]weather.ForecastDay, len(observed))
+	for _, d := range observed {
+		obs[d.Date.Format(time.DateOnly)] = d
+	}
+
+	byLead := make(map[int]*LeadAccuracy)
+	for _, f := range forecasts {
+		o, ok := obs[f.Day.Date.Format(time.DateOnly)]
+		if !ok {
+			continue
+		}
+		fetched := f.FetchedAt.UTC()
+		lead := int(math.Round(f.Day.Date.Sub(time.Date(fetched.Year(), fetched.Month(), fetched.Day(), 0, 0, 0, 0, time.UTC)).Hours() / 24))
+		if lead < 1 {
+			continue
+		}
+		l := byLead[lead]
+		if l == nil {
+			l = &LeadAccuracy{LeadDays: lead}
+			byLead[lead] = l
+		}
+		l.Samples++
+		l.SpeedMAE += math.Abs(f.Day.WindSpeedMax - o.WindSpeedMax) // summed until the end
+		if !f.Day.DirUnknown && !o.DirUnknown {
+			l.DirectionSamples++
+			if dayEasterly(f.Day, minSpeed) == dayEasterly(o, minSpeed) {
+				l.DirectionHits++
+			}
+		}
+	}
+
+	out := make([]LeadAccuracy, 0, len(byLead))
+	for _, l := range byLead {
+		l.SpeedMAE /= float64(l.Samples)
+		out = append(out, *l)
+	}
+	slices.SortFunc(out, func(x, y LeadAccuracy) int { return x.LeadDays - y.LeadDays })
+	return out
+}
+
+// accuracyReport sends the weekly forecast accuracy report, on the first
+// wind check of each WeekStart day.
+func (a *Agent) accuracyReport(ctx context.Context, at time.Time) error {
+	if !a.cfg.AccuracyReport {
+		return nil
+	}
+	local := at.In(a.london)
+	if local.Weekday() != a.weekStart {
+		return nil
+	}
+	a.stateMu.Lock()
+	last, ran := a.state.LastRuns[checkAccuracy]
+	a.stateMu.Unlock()
+	if ran && sameDay(last.In(a.london), local) {
+		return nil
+	}
+	history, ok := a.cfg.RunLog.(WindHistory)
+	if !ok {
+		return errors.New("accuracy report needs a run log that implements WindHistory")
+	}
+
+	forecasts, err := history.WindForecasts(ctx, local.AddDate(0, 0, -accuracyWindow))
+	if err != nil {
+		return fmt.Errorf("accuracy report: %w", err)
+	}
+	observed, err := retry(ctx, a.policy.FetchRetries, a.policy.RetryDelay, "observed wind fetch", func() ([]weather.ForecastDay, error) {
+		return a.cfg.WindWeather.FetchObserved(ctx, accuracyWindow)
+	})
+	if err != nil {
+		return fmt.Errorf("accuracy report: fetch observed wind: %w", err)
+	}
+
+	r := Report{
+		Kind:     checkAccuracy,
+		Location: a.cfg.WindLocation,
+		Headline: a.tr.T(msgAccuracyTitle, accuracyWindow),
+		IssuedAt: at,
+	}
+	if leads := ForecastAccuracy(forecasts, observed, a.cfg.MinEasterlySpeed); len(leads) > 0 {
+		r.Table = accuracyTable(leads, a.tr)
+	} else {
+		r.Headline += "\n" + a.tr.T(msgAccuracyNone)
+	}
+	a.writeSink(at, r.Location+" accuracy", r)
+	a.writeMarkdown(r)
+	a.markRan(checkAccuracy, at)
+	if err := a.deliver(ctx, r); err != nil {
+		return fmt.Errorf("accuracy notify: %w", err)
+	}
+	return nil
+}
+
+// accuracyTable lays out one row per lead time.
+func accuracyTable(leads []LeadAccuracy, tr translator) string {
+	var buf strings.Builder
+	w := tabwriter.NewWriter(&buf, 0, 0, 0, ' ', tabwriter.Debug)
+	writeRow(w, []string{tr.T(msgColLead), tr.T(msgColSamples), tr.T(msgColMAE), tr.T(msgColDirHits)})
+	for _, l := range leads {
+		hits := "—"
+		if l.DirectionSamples > 0 {
+			hits = fmt.Sprintf("%.0f%%", 100*l.DirectionHitRate())
+		}
+		writeRow(w, []string{fmt.Sprintf("%dd", l.LeadDays), fmt.Sprint(l.Samples), fmt.Sprintf("%.1f", l.SpeedMAE), hits})
+	}
+	_ = w.Flush()
+
+	lines := strings.SplitAfterN(buf.String(), "\n", 2)
+	if len(lines) < 2 {
+		return buf.String()
+	}
+	return lines[0] + tableRule(lines[0]) + lines[1]
+}
@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+func TestForecastAccuracy(t *testing.T) {
+	jan := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	day := func(d int, speed, dir float64) weather.ForecastDay {
+		return weather.ForecastDay{Date: jan(d), WindSpeedMax: speed, WindDirMean: dir}
+	}
+	stored := func(fetched, d int, speed, dir float64) StoredForecast {
+		return StoredForecast{FetchedAt: jan(fetched).Add(10 * time.Hour), Day: day(d, speed, dir)}
+	}
+	unknown := stored(8, 10, 10, 0)
+	unknown.Day.DirUnknown = true
+	observed := []weather.ForecastDay{day(10, 20, 90), day(11, 30, 270)}
+
+	tests := []struct {
+		name      string
+		forecasts []StoredForecast
+		minSpeed  float64
+		want      []LeadAccuracy
+	}{
+		{"by lead time", []StoredForecast{
+			stored(9, 10, 24, 100), // 1 day ahead: 4 km/h off, easterly as observed
+			stored(9, 11, 20, 90),  // 2 days ahead: 10 km/h off, easterly but westerly observed
+			stored(10, 11, 31, 280),
+			unknown,
+		}, 0, []LeadAccuracy{
+			{LeadDays: 1, Samples: 2, SpeedMAE: 2.5, DirectionSamples: 2, DirectionHits: 2},
+			{LeadDays: 2, Samples: 2, SpeedMAE: 10, DirectionSamples: 1, DirectionHits: 0},
+		}},
+		{"same-day and unobserved forecasts skipped", []StoredForecast{
+			stored(10, 10, 24, 100),
+			stored(9, 12, 24, 100),
+		}, 0, []LeadAccuracy{}},
+		{"observed day too light to count as easterly", []StoredForecast{
+			stored(9, 10, 24, 100),
+		}, 22, []LeadAccuracy{
+			{LeadDays: 1, Samples: 1, SpeedMAE: 4, DirectionSamples: 1, DirectionHits: 0},
+		}},
+	}
+	for _, tt := range tests {
+		if got := ForecastAccuracy(tt.forecasts, observed, tt.minSpeed); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDirectionHitRate(t *testing.T) {
+	tests := []struct {
+		l    LeadAccuracy
+		want float64
+	}{
+		{LeadAccuracy{}, 0},
+		{LeadAccuracy{DirectionSamples: 4, DirectionHits: 3}, 0.75},
+	}
+	for _, tt := range tests {
+		if got := tt.l.DirectionHitRate(); got != tt.want {
+			t.Errorf("%+v: hit rate %v, want %v", tt.l, got, tt.want)
+		}
+	}
+}
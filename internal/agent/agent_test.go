@@ -0,0 +1,225 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/ollama"
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// testNow is the frozen clock of test agents, a Monday morning.
+var testNow = time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+
+// testWind is a three-day Open-Meteo wind response: easterly, westerly,
+// easterly.
+const testWind = `{"daily":{
+	"time":["2025-01-06","2025-01-07","2025-01-08"],
+	"windspeed_10m_max":[25,18,30],
+	"windgusts_10m_max":[40,31,52],
+	"winddirection_10m_dominant":[90,270,100]
+}}`
+
+// testRain is a one-day Open-Meteo rain response without hourly detail.
+const testRain = `{"daily":{"time":["2025-01-06"],"precipitation_sum":[0],"precipitation_probability_max":[10]},
+	"hourly":{"time":[],"precipitation_probability":[],"precipitation":[]}}`
+
+// serveJSON starts a server answering every request with body.
+func serveJSON(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// fakeWeather returns an Open-Meteo client whose requests get body.
+func fakeWeather(t *testing.T, body string) *weather.OpenMeteoClient {
+	t.Helper()
+	return &weather.OpenMeteoClient{BaseURL: serveJSON(t, body).URL}
+}
+
+// recordingNotifier keeps the reports it is sent, failing with err if set.
+type recordingNotifier struct {
+	name string
+	err  error
+
+	mu      sync.Mutex
+	reports []Report
+}
+
+func (n *recordingNotifier) Name() string { return n.name }
+
+func (n *recordingNotifier) Notify(ctx context.Context, r Report) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.reports = append(n.reports, r)
+	return n.err
+}
+
+func (n *recordingNotifier) sent() []Report {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Report(nil), n.reports...)
+}
+
+// newTestAgent fills cfg's unset weather clients, Ollama and clock with
+// fakes serving testWind, testRain and a fixed summary, and returns the agent.
+func newTestAgent(t *testing.T, cfg Config) *Agent {
+	t.Helper()
+	if cfg.WindWeather == nil {
+		cfg.WindWeather = fakeWeather(t, testWind)
+	}
+	if cfg.RainWeather == nil {
+		cfg.RainWeather = fakeWeather(t, testRain)
+	}
+	if cfg.Ollama == nil {
+		cfg.Ollama = &ollama.Client{Host: serveJSON(t, `{"response":"Mostly easterly."}`).URL}
+	}
+	if cfg.WindLocation == "" {
+		cfg.WindLocation = "Heathrow"
+	}
+	if cfg.RainLocation == "" {
+		cfg.RainLocation = "Twickenham"
+	}
+	if cfg.Now == nil {
+		cfg.Now = func() time.Time { return testNow }
+	}
+	if cfg.Policy == nil {
+		p := DefaultPolicy()
+		p.RetryDelay = 0
+		cfg.Policy = &p
+	}
+	return New(cfg)
+}
+
+func TestEmptySummaryFallsBackToTable(t *testing.T) {
+	n := &recordingNotifier{name: "test"}
+	a := newTestAgent(t, Config{
+		Ollama:    &ollama.Client{Host: serveJSON(t, `{"response":"  "}`).URL},
+		Notifiers: []Notifier{n},
+	})
+
+	err := a.RunOnce(context.Background())
+	if !errors.Is(err, errEmptySummary) {
+		t.Fatalf("RunOnce error = %v, want errEmptySummary", err)
+	}
+	var wind *Report
+	for _, r := range n.sent() {
+		if r.Kind == checkWind {
+			wind = &r
+		}
+	}
+	if wind == nil {
+		t.Fatal("no wind report sent")
+	}
+	if wind.Summary != "" || wind.Table == "" {
+		t.Errorf("wind report summary %q, table %q; want the table alone", wind.Summary, wind.Table)
+	}
+}
+
+// testDays is testWind as fetched.
+func testDays(t *testing.T) []weather.ForecastDay {
+	t.Helper()
+	days, err := fakeWeather(t, testWind).Fetch(context.Background(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return days
+}
+
+func TestVariableDays(t *testing.T) {
+	a := newTestAgent(t, Config{HourlyDirection: true})
+	days := testDays(t)
+	if got := a.variableDays(days); got != "" {
+		t.Errorf("variableDays = %q, want none", got)
+	}
+
+	days[1].DirVariability = 95
+	if got, want := a.variableDays(days), "Variable winds (~): Tue"; got != want {
+		t.Errorf("variableDays = %q, want %q", got, want)
+	}
+}
+
+func TestUnknownDirection(t *testing.T) {
+	days := testDays(t)
+	days[1].DirUnknown = true
+	tr := newTranslator("en")
+	if got := dayCompass(days[1], tr); got != "—" {
+		t.Errorf("dayCompass = %q, want —", got)
+	}
+	// Unknown days count as neither easterly nor westerly.
+	days[0].DirUnknown = true
+	if got := countEasterlyDays(days, 0); got != 1 {
+		t.Errorf("countEasterlyDays = %d, want 1", got)
+	}
+}
+
+func TestExtraWindLocations(t *testing.T) {
+	n := &recordingNotifier{name: "test"}
+	a := newTestAgent(t, Config{
+		ExtraWindLocations: []Location{
+			{Name: "Gatwick", Latitude: 51.15, Longitude: -0.18},
+			{Name: "Stansted", Latitude: 51.89, Longitude: 0.24},
+		},
+		FetchConcurrency: 1,
+		Notifiers:        []Notifier{n},
+	})
+	if err := a.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	extra := map[string]bool{}
+	for _, r := range n.sent() {
+		if r.Kind == checkWind && r.Location != "Heathrow" {
+			extra[r.Location] = true
+		}
+	}
+	if !extra["Gatwick"] || !extra["Stansted"] || len(extra) != 2 {
+		t.Errorf("extra wind reports for %v, want Gatwick and Stansted", extra)
+	}
+}
+
+func TestWindSpeedSymbol(t *testing.T) {
+	mph := &weather.OpenMeteoClient{WindSpeedUnit: "mph"}
+	a := newTestAgent(t, Config{WindWeather: mph, GustAlertThreshold: 30})
+	day := weather.ForecastDay{Date: testNow, WindSpeedMax: 20, WindGustMax: 35, WindDirMean: 90}
+	for name, got := range map[string]string{
+		"all clear": a.allClear(day),
+		"windiest":  a.windiestLine([]weather.ForecastDay{day}),
+		"alert":     a.gustAlert([]weather.ForecastDay{day}),
+	} {
+		if !strings.Contains(got, "mph") || strings.Contains(got, "km/h") {
+			t.Errorf("%s: %q, want the speed in mph", name, got)
+		}
+	}
+	// 20 mph is 32 km/h: moderate on the Beaufort-based scales, not light.
+	if got := windStrength(a.kmh(day.WindSpeedMax), a.tr); got != a.tr.T(msgModerate) {
+		t.Errorf("windStrength(20 mph) = %q, want moderate", got)
+	}
+}
+
+func TestSpeedSettingsInKmh(t *testing.T) {
+	mph := &weather.OpenMeteoClient{WindSpeedUnit: "mph"}
+	// 50 km/h is about 31 mph, so 33 mph gusts reach it and 30 mph don't.
+	a := newTestAgent(t, Config{WindWeather: mph, GustAlertThreshold: 50, MinEasterlySpeed: 16})
+	gusty := weather.ForecastDay{Date: testNow, WindSpeedMax: 12, WindGustMax: 33, WindDirMean: 90}
+	if a.gustAlert([]weather.ForecastDay{gusty}) == "" {
+		t.Error("33 mph gusts: no alert, want one for the 50 km/h threshold")
+	}
+	gusty.WindGustMax = 30
+	if got := a.gustAlert([]weather.ForecastDay{gusty}); got != "" {
+		t.Errorf("30 mph gusts: alert %q, want none for the 50 km/h threshold", got)
+	}
+	// 12 mph is about 19 km/h, above the 16 km/h minimum.
+	if !dayEasterly(gusty, a.cfg.MinEasterlySpeed) {
+		t.Error("12 mph easterly: not counted, want it above the 16 km/h minimum")
+	}
+}
@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// gustAlertKey names the gust alert condition, used to track its cooldown
+// in the persisted state.
+const gustAlertKey = "gust"
+
+// sendAlerts notifies about each alert condition in the forecast, honouring
+// cooldowns and quiet hours. Alerts carry the forecast severity so they can
+// be routed separately from routine reports.
+func (a *Agent) sendAlerts(ctx context.Context, days []weather.ForecastDay, at time.Time) error {
+	if a.inQuietHours(a.now()) {
+		return nil
+	}
+	var errs []error
+	active := false
+	for _, c := range []struct{ key, text string }{
+		{gustAlertKey, a.gustAlert(days)},
+	} {
+		active = active || c.text != ""
+		if !a.shouldAlert(c.key, c.text != "", a.now()) {
+			continue
+		}
+		fmt.Println(c.text)
+		r := Report{
+			Kind:     "alert",
+			Location: a.cfg.WindLocation,
+			Headline: c.text,
+			Severity: max(a.forecastSeverity(days), SeverityWarning),
+			IssuedAt: at,
+		}
+		if _, err := a.notify(ctx, r); err != nil {
+			errs = append(errs, fmt.Errorf("%s alert: %w", c.key, err))
+		}
+	}
+	if !active && a.cfg.SendAllClear && len(days) > 0 {
+		r := Report{
+			Kind:     "all-clear",
+			Location: a.cfg.WindLocation,
+			Headline: a.allClear(days[0]),
+			Severity: SeverityInfo,
+			IssuedAt: at,
+		}
+		if _, err := a.notify(ctx, r); err != nil {
+			errs = append(errs, fmt.Errorf("all-clear: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// allClear is the heartbeat sent when no alert condition is active,
+// describing today's wind.
+func (a *Agent) allClear(today weather.ForecastDay) string {
+	return a.tr.T(msgAllClear, today.WindSpeedMax, a.windSymbol(), dayCompass(today, a.tr))
+}
+
+// gustAlert returns the alert text when any forecast day reaches the gust
+// threshold, or "" when the condition is clear.
+func (a *Agent) gustAlert(days []weather.ForecastDay) string {
+	if a.cfg.GustAlertThreshold <= 0 {
+		return ""
+	}
+	for _, d := range days {
+		if d.WindGustMax >= a.cfg.GustAlertThreshold {
+			return fmt.Sprintf("💨 Windy! Gusts up to %.0f %s on %s", d.WindGustMax, a.windSymbol(), a.tr.Day(d.Date))
+		}
+	}
+	return ""
+}
+
+// shouldAlert records the state of an alert condition and reports whether a
+// notification should go out. Once fired, a condition stays quiet until it
+// clears and re-triggers, or until AlertCooldown has elapsed.
+func (a *Agent) shouldAlert(key string, active bool, now time.Time) bool {
+	send := false
+	err := a.updateState(func(st *state) {
+		last, seen := st.LastAlerts[key]
+		if !active {
+			delete(st.LastAlerts, key)
+			return
+		}
+		if seen && now.Sub(last) < a.cfg.AlertCooldown {
+			fmt.Printf("alert %q suppressed, last sent %s\n", key, last.Format(time.RFC3339))
+			return
+		}
+		if st.LastAlerts == nil {
+			st.LastAlerts = make(map[string]time.Time)
+		}
+		st.LastAlerts[key] = now
+		send = true
+	})
+	if err != nil {
+		fmt.Printf("warning: save state: %v\n", err)
+	}
+	return send
+}
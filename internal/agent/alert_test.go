@@ -0,0 +1,62 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// fakeTelegram records the chat of every sendMessage call.
+type fakeTelegram struct {
+	mu    sync.Mutex
+	chats []string
+	modes []string
+}
+
+func newFakeTelegram(t *testing.T) (*fakeTelegram, string) {
+	t.Helper()
+	f := &fakeTelegram{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err == nil {
+			f.mu.Lock()
+			f.chats = append(f.chats, msg.ChatID)
+			f.modes = append(f.modes, msg.ParseMode)
+			f.mu.Unlock()
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return f, srv.URL
+}
+
+func TestAlertChatRouting(t *testing.T) {
+	tg, url := newFakeTelegram(t)
+	a := New(Config{
+		TelegramToken:       "token",
+		TelegramChatID:      "1",
+		TelegramAlertChatID: "2",
+		TelegramBaseURL:     url,
+		TelegramParseMode:   "HTML",
+	})
+
+	for _, sev := range []Severity{SeverityInfo, SeverityWarning, SeveritySevere} {
+		if _, err := a.notify(context.Background(), Report{Kind: "test", Headline: "x", Severity: sev}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	if want := []string{"1", "2", "2"}; !slices.Equal(tg.chats, want) {
+		t.Errorf("sent to chats %v, want %v", tg.chats, want)
+	}
+	for _, mode := range tg.modes {
+		if mode != "HTML" {
+			t.Errorf("parse mode %q, want the configured HTML", mode)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"math"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// Dominant directions reported by EasterlyAnalysis.
+const (
+	DominantEast  = "E"
+	DominantWest  = "W"
+	DominantMixed = "Mixed"
+)
+
+// EasterlyAnalysis is the easterly/westerly breakdown of a forecast.
+type EasterlyAnalysis struct {
+	EastDays int
+	WestDays int
+	// LightEasterlyDays are easterly but below the minimum speed, and count
+	// as neither direction. Days without a direction are left out entirely.
+	LightEasterlyDays int
+	// Dominant is DominantEast, DominantWest or DominantMixed (a tie).
+	Dominant string
+	// Decisive reports whether Dominant leads by more than the margin;
+	// otherwise it is only "mostly" that direction.
+	Decisive bool
+	// LongestStreak is the first longest run of easterly days, with zero
+	// Days when there are none.
+	LongestStreak Streak
+}
+
+// EastPercent returns the share of directional days that are easterly,
+// rounded, and 0 when there are none.
+func (e EasterlyAnalysis) EastPercent() int {
+	known := e.EastDays + e.WestDays
+	if known == 0 {
+		return 0
+	}
+	return int(math.Round(100 * float64(e.EastDays) / float64(known)))
+}
+
+// WestPercent returns 100 - EastPercent, or 0 when there are no directional
+// days.
+func (e EasterlyAnalysis) WestPercent() int {
+	if e.EastDays+e.WestDays == 0 {
+		return 0
+	}
+	return 100 - e.EastPercent()
+}
+
+// AnalyzeEasterly counts easterly and westerly days and picks the dominant
+// direction, decisive only when it leads by more than margin days. minSpeed
+// is as for Config.MinEasterlySpeed.
+func AnalyzeEasterly(days []weather.ForecastDay, margin int, minSpeed float64) EasterlyAnalysis {
+	e := EasterlyAnalysis{
+		EastDays:          countEasterlyDays(days, minSpeed),
+		LightEasterlyDays: countLightEasterly(days, minSpeed),
+	}
+	e.WestDays = len(days) - e.EastDays - e.LightEasterlyDays - countUnknownDirection(days)
+
+	switch lead := e.EastDays - e.WestDays; {
+	case lead > 0:
+		e.Dominant = DominantEast
+		e.Decisive = lead > margin
+	case lead < 0:
+		e.Dominant = DominantWest
+		e.Decisive = -lead > margin
+	default:
+		e.Dominant = DominantMixed
+	}
+	e.LongestStreak, _ = longestStreak(EasterlyStreaks(days, minSpeed))
+	return e
+}
+
+// buildEasterlyAnalysis formats AnalyzeEasterly as the report's summary
+// lines, marking a decisive easterly majority with marker.
+func buildEasterlyAnalysis(days []weather.ForecastDay, margin int, minSpeed float64, marker string, tr translator) string {
+	e := AnalyzeEasterly(days, margin, minSpeed)
+
+	var dominant string
+	switch {
+	case e.Dominant == DominantEast && e.Decisive:
+		dominant = tr.T(msgEast) + " " + marker
+	case e.Dominant == DominantWest && e.Decisive:
+		dominant = tr.T(msgWest)
+	case e.Dominant == DominantEast:
+		dominant = tr.T(msgMostly, tr.T(msgEast), tr.T(msgWest))
+	case e.Dominant == DominantWest:
+		dominant = tr.T(msgMostly, tr.T(msgWest), tr.T(msgEast))
+	default:
+		dominant = tr.T(msgMixed)
+	}
+
+	result := tr.T(msgDominant, dominant, e.EastDays, e.EastPercent(), e.WestDays, e.WestPercent())
+	if s := e.LongestStreak; s.Days > 0 {
+		key := msgLongestStreak
+		if s.Days == 1 {
+			key = msgLongestStreakOne
+		}
+		result += tr.T(key, streakRange(s, tr), s.Days) + "\n"
+	}
+	return result
+}
@@ -0,0 +1,58 @@
+package agent
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// minWindChange is the smallest change in max wind (km/h) worth reporting.
+// Changes are converted from the wind check's unit before comparing.
+const minWindChange = 5
+
+// swapLastForecast saves days as the forecast for the next wind check to
+// compare against and returns the previous one, nil on the first run.
+func (a *Agent) swapLastForecast(days []weather.ForecastDay) []weather.ForecastDay {
+	var prev []weather.ForecastDay
+	err := a.updateState(func(st *state) {
+		prev = st.LastForecast
+		st.LastForecast = days
+	})
+	if err != nil {
+		fmt.Printf("warning: save state: %v\n", err)
+	}
+	return prev
+}
+
+// forecastChanges describes how the forecast moved since the previous wind
+// check, then saves days for the next comparison. It returns "" on the first
+// run or when no day changed notably.
+func (a *Agent) forecastChanges(days []weather.ForecastDay) string {
+	prev := a.swapLastForecast(days)
+	if len(prev) == 0 {
+		return ""
+	}
+
+	var lines []string
+	for _, d := range weather.DiffForecasts(prev, days) {
+		var parts []string
+		switch {
+		case a.kmh(d.WindChange) >= minWindChange:
+			parts = append(parts, a.tr.T(msgWindUp, d.WindChange, a.windSymbol()))
+		case a.kmh(d.WindChange) <= -minWindChange:
+			parts = append(parts, a.tr.T(msgWindDown, math.Abs(d.WindChange), a.windSymbol()))
+		}
+		if from, to := degToCompass(d.PrevDir, a.tr), degToCompass(d.CurrDir, a.tr); !d.DirUnknown && from != to {
+			parts = append(parts, from+"→"+to)
+		}
+		if len(parts) > 0 {
+			lines = append(lines, fmt.Sprintf("  %s: %s", a.tr.Day(d.Date), strings.Join(parts, ", ")))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return a.tr.T(msgChanges) + "\n" + strings.Join(lines, "\n")
+}
@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Forecast table columns, see Config.TableColumns.
+const (
+	ColumnDate    = "date"
+	ColumnWind    = "wind"
+	ColumnDir     = "dir"
+	ColumnTemp    = "temp"    // min/max temperature
+	ColumnTempBar = "tempbar" // min-max temperature bar
+	ColumnGust    = "gust"    // Theme.Gust on days reaching GustMarkerThreshold
+	ColumnEast    = "east"    // direction marker
+)
+
+// tableColumns lists every column name.
+var tableColumns = []string{ColumnDate, ColumnWind, ColumnDir, ColumnTemp, ColumnTempBar, ColumnGust, ColumnEast}
+
+// ParseTableColumns parses a comma-separated list of column names such as
+// "date,wind,dir,east", rejecting unknown and repeated names. An empty list
+// returns nil, keeping the default columns.
+func ParseTableColumns(s string) ([]string, error) {
+	var cols []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(tableColumns, name) {
+			return nil, fmt.Errorf("unknown table column %q (known: %s)", name, strings.Join(tableColumns, ", "))
+		}
+		if slices.Contains(cols, name) {
+			return nil, fmt.Errorf("table column %q listed twice", name)
+		}
+		cols = append(cols, name)
+	}
+	return cols, nil
+}
+
+// defaultTableColumns returns the columns shown when TableColumns is unset:
+// date, wind and direction, the optional columns the other options turn on,
+// then the direction marker.
+func (c Config) defaultTableColumns() []string {
+	cols := []string{ColumnDate, ColumnWind, ColumnDir}
+	if c.ShowTemperature {
+		cols = append(cols, ColumnTemp)
+	}
+	if c.TemperatureBars {
+		cols = append(cols, ColumnTempBar)
+	}
+	if c.GustMarkerThreshold > 0 {
+		cols = append(cols, ColumnGust)
+	}
+	return append(cols, ColumnEast)
+}
+
+// columnHeader returns a column's translated heading.
+func (a *Agent) columnHeader(col string) string {
+	switch col {
+	case ColumnDate:
+		return a.tr.T(msgColDate)
+	case ColumnWind:
+		return a.tr.T(msgColWind)
+	case ColumnDir:
+		return a.tr.T(msgColDir)
+	case ColumnTemp:
+		return a.tr.T(msgColTemp) + a.cfg.WindWeather.TemperatureSymbol()
+	case ColumnTempBar:
+		return a.tr.T(msgColTempRange)
+	case ColumnGust:
+		return a.tr.T(msgColGust)
+	default:
+		return a.tr.T(msgColEast)
+	}
+}
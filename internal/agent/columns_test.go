@@ -0,0 +1,71 @@
+package agent
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+func TestParseTableColumns(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr string
+	}{
+		{"", nil, ""},
+		{" , ", nil, ""},
+		{"east,date", []string{ColumnEast, ColumnDate}, ""},
+		{" Date, WIND ,,dir ", []string{ColumnDate, ColumnWind, ColumnDir}, ""},
+		{"date,rain", nil, `unknown table column "rain"`},
+		{"date,wind,Date", nil, `table column "date" listed twice`},
+	}
+	for _, tt := range tests {
+		got, err := ParseTableColumns(tt.in)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseTableColumns(%q) error %v, want %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("ParseTableColumns(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestDefaultTableColumns(t *testing.T) {
+	configs := []Config{
+		{},
+		{ShowTemperature: true, GustMarkerThreshold: 50},
+		{ShowTemperature: true, TemperatureBars: true, GustMarkerThreshold: 50},
+	}
+	for _, cfg := range configs {
+		cfg.Now = func() time.Time { return testNow }
+		cfg.WindWeather = &weather.OpenMeteoClient{}
+		want := New(cfg).buildForecastTable(sampleForecast())
+
+		cols, err := ParseTableColumns(strings.Join(cfg.defaultTableColumns(), ","))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.TableColumns = cols
+		if got := New(cfg).buildForecastTable(sampleForecast()); got != want {
+			t.Errorf("columns %q:\n%s\nwant the default table:\n%s", cols, got, want)
+		}
+	}
+}
+
+func TestTableColumnsOrder(t *testing.T) {
+	a := New(Config{
+		TableColumns: []string{ColumnEast, ColumnDate},
+		Now:          func() time.Time { return testNow },
+		WindWeather:  &weather.OpenMeteoClient{},
+	})
+	header := strings.Split(a.buildForecastTable(sampleForecast()), "\n")[0]
+	if want := "East | Date"; header != want {
+		t.Errorf("header %q, want %q", header, want)
+	}
+}
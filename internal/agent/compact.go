@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// defaultCompactTolerance is the CompactTolerance used when unset, in km/h
+// whatever the wind check's unit.
+const defaultCompactTolerance = 5
+
+// compactRuns splits the table rows into runs drawn as one row each. Without
+// CompactTable every row is its own run. With it, consecutive rows for
+// adjacent dates join a run when they share the direction, markers and
+// horizon status and every speed in the run stays within CompactTolerance of
+// the others, so a slow drift can't chain into one row. Today and tomorrow
+// stay apart under RelativeDates, keeping their labels.
+func (a *Agent) compactRuns(rows []weather.ForecastDay) [][]weather.ForecastDay {
+	var runs [][]weather.ForecastDay
+	lo, hi := 0.0, 0.0
+	for _, d := range rows {
+		if n := len(runs); n > 0 && a.cfg.CompactTable && a.joinsRun(runs[n-1], d, lo, hi) {
+			runs[n-1] = append(runs[n-1], d)
+			lo, hi = min(lo, d.WindSpeedMax), max(hi, d.WindSpeedMax)
+			continue
+		}
+		runs = append(runs, []weather.ForecastDay{d})
+		lo, hi = d.WindSpeedMax, d.WindSpeedMax
+	}
+	return runs
+}
+
+// joinsRun reports whether d can extend run, whose speeds span lo..hi.
+func (a *Agent) joinsRun(run []weather.ForecastDay, d weather.ForecastDay, lo, hi float64) bool {
+	last := run[len(run)-1]
+	tol := a.cfg.CompactTolerance
+	if tol <= 0 {
+		tol = a.fromKmh(defaultCompactTolerance)
+	}
+	relative := func(d weather.ForecastDay) bool { return a.dayLabel(d.Date) != a.tr.Day(d.Date) }
+	return (sameDay(last.Date.AddDate(0, 0, 1), d.Date) || sameDay(last.Date.AddDate(0, 0, -1), d.Date)) &&
+		!relative(last) && !relative(d) &&
+		dayCompass(d, a.tr) == dayCompass(last, a.tr) &&
+		a.directionMarker(d) == a.directionMarker(last) &&
+		a.variableMarker(d) == a.variableMarker(last) &&
+		a.cfg.Theme.windMarker(a.kmh(d.WindSpeedMax)) == a.cfg.Theme.windMarker(a.kmh(last.WindSpeedMax)) &&
+		a.beyondHorizon(d) == a.beyondHorizon(last) &&
+		max(hi, d.WindSpeedMax)-min(lo, d.WindSpeedMax) <= tol
+}
+
+// runLabel names a run's dates: "Mon–Thu" within a week, or the full first
+// and last dates for longer runs.
+func (a *Agent) runLabel(run []weather.ForecastDay) string {
+	first := slices.MinFunc(run, func(x, y weather.ForecastDay) int { return x.Date.Compare(y.Date) }).Date
+	last := slices.MaxFunc(run, func(x, y weather.ForecastDay) int { return x.Date.Compare(y.Date) }).Date
+	if last.Sub(first) < 7*24*time.Hour {
+		names := weekdayNames[a.tr.lang]
+		return fmt.Sprintf("%s–%s", names[first.Weekday()], names[last.Weekday()])
+	}
+	return fmt.Sprintf("%s–%s", a.tr.Day(first), a.tr.Day(last))
+}
+
+// meanSpeed averages the max wind speeds of days.
+func meanSpeed(days []weather.ForecastDay) float64 {
+	var sum float64
+	for _, d := range days {
+		sum += d.WindSpeedMax
+	}
+	return sum / float64(len(days))
+}
+
+// tempRange returns the lowest min and highest max temperature over days,
+// and false when any day lacks them.
+func tempRange(days []weather.ForecastDay) (lo, hi float64, ok bool) {
+	for i, d := range days {
+		if !hasTemperature(d) {
+			return 0, 0, false
+		}
+		if i == 0 {
+			lo, hi = d.TempMin, d.TempMax
+			continue
+		}
+		lo, hi = min(lo, d.TempMin), max(hi, d.TempMax)
+	}
+	return lo, hi, len(days) > 0
+}
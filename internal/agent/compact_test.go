@@ -0,0 +1,43 @@
+package agent
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+func TestCompactRuns(t *testing.T) {
+	// Westerly days from Monday 6 January 2025, all in the same wind band.
+	westerly := func(speeds ...float64) []weather.ForecastDay {
+		days := make([]weather.ForecastDay, len(speeds))
+		for i, s := range speeds {
+			days[i] = weather.ForecastDay{Date: time.Date(2025, 1, 6+i, 0, 0, 0, 0, time.UTC), WindSpeedMax: s, WindDirMean: 270}
+		}
+		return days
+	}
+
+	tests := []struct {
+		name      string
+		days      []weather.ForecastDay
+		tolerance float64
+		want      []int // run lengths
+	}{
+		{"exactly at the tolerance", westerly(20, 25), 0, []int{2}},
+		{"just over the tolerance", westerly(20, 25.5), 0, []int{1, 1}},
+		{"slow drift doesn't chain", westerly(20, 24, 28, 32), 0, []int{2, 2}},
+		{"configured tolerance", westerly(20, 24, 28, 32), 10, []int{3, 1}},
+		{"direction change", append(westerly(20), weather.ForecastDay{Date: time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC), WindSpeedMax: 20, WindDirMean: 90}), 0, []int{1, 1}},
+	}
+	for _, tt := range tests {
+		a := newTestAgent(t, Config{CompactTable: true, CompactTolerance: tt.tolerance})
+		var got []int
+		for _, run := range a.compactRuns(tt.days) {
+			got = append(got, len(run))
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: run lengths %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
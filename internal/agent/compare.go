@@ -0,0 +1,130 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// comparisonReport fetches CompareWindLocation and sends one report setting
+// its forecast beside the main wind location's, aligned by date.
+func (a *Agent) comparisonReport(ctx context.Context, forecast []weather.ForecastDay, at time.Time) error {
+	other := a.cfg.CompareWindLocation
+	if other == nil {
+		return nil
+	}
+	c := *a.cfg.WindWeather
+	c.Latitude, c.Longitude = other.Latitude, other.Longitude
+	otherDays, err := retry(ctx, a.policy.FetchRetries, a.policy.RetryDelay, other.Name+" wind fetch", func() ([]weather.ForecastDay, error) {
+		return c.Fetch(ctx, a.cfg.WindDays)
+	})
+	if err != nil {
+		return fmt.Errorf("%s wind forecast: %w", other.Name, err)
+	}
+
+	mainName := a.cfg.WindLocation
+	headline := []string{
+		a.comparisonLine(mainName, forecast),
+		a.comparisonLine(other.Name, otherDays),
+	}
+	table, missing := a.comparisonTable(mainName, forecast, other.Name, otherDays)
+	headline = append(headline, missing...)
+
+	r := Report{
+		Kind:     checkWind,
+		Location: mainName + " / " + other.Name,
+		Headline: strings.Join(headline, "\n"),
+		Table:    table,
+		Footer:   a.issuedFooter(at),
+		IssuedAt: at,
+	}
+	a.writeSink(at, r.Location+" wind", r)
+	a.writeMarkdown(r)
+	if err := a.deliver(ctx, r); err != nil {
+		return fmt.Errorf("%s wind notify: %w", r.Location, err)
+	}
+	return nil
+}
+
+// comparisonLine says when a location is easterly, e.g. "Gatwick easterly
+// Tue–Thu", or that it stays westerly.
+func (a *Agent) comparisonLine(name string, days []weather.ForecastDay) string {
+	days = a.reliableDays(days)
+	if len(days) == 0 {
+		return a.tr.T(msgCompareNoData, name)
+	}
+	if ranges := easterlyDayRanges(days, a.cfg.MinEasterlySpeed, a.tr); ranges != "" {
+		return a.tr.T(msgCompareEasterly, name, ranges)
+	}
+	return a.tr.T(msgCompareWesterly, name)
+}
+
+// comparisonTable lays out both forecasts by date over the union of their
+// days, showing "—" where one location has no data. It also returns a note
+// for each location missing days the other has.
+func (a *Agent) comparisonTable(nameA string, daysA []weather.ForecastDay, nameB string, daysB []weather.ForecastDay) (string, []string) {
+	tr := a.tr
+	byDate := func(days []weather.ForecastDay) map[string]weather.ForecastDay {
+		m := make(map[string]weather.ForecastDay, len(days))
+		for _, d := range days {
+			m[d.Date.Format(time.DateOnly)] = d
+		}
+		return m
+	}
+	mapA, mapB := byDate(daysA), byDate(daysB)
+
+	var dates []time.Time
+	seen := make(map[string]bool)
+	for _, d := range slices.Concat(daysA, daysB) {
+		key := d.Date.Format(time.DateOnly)
+		if !seen[key] {
+			seen[key] = true
+			dates = append(dates, d.Date)
+		}
+	}
+	slices.SortFunc(dates, func(x, y time.Time) int { return x.Compare(y) })
+
+	cell := func(m map[string]weather.ForecastDay, key string) string {
+		d, ok := m[key]
+		if !ok {
+			return "—"
+		}
+		s := fmt.Sprintf("%.*f %s", a.cfg.WindDecimals, d.WindSpeedMax, dayCompass(d, tr))
+		return withMarker(s, a.directionMarker(d))
+	}
+
+	var buf strings.Builder
+	w := tabwriter.NewWriter(&buf, 0, 0, 0, ' ', tabwriter.Debug)
+	writeRow(w, []string{tr.T(msgColDate), nameA, nameB})
+	var missingA, missingB []string
+	for _, d := range dates {
+		key := d.Format(time.DateOnly)
+		if _, ok := mapA[key]; !ok {
+			missingA = append(missingA, tr.Day(d))
+		}
+		if _, ok := mapB[key]; !ok {
+			missingB = append(missingB, tr.Day(d))
+		}
+		writeRow(w, []string{a.dayLabel(d), cell(mapA, key), cell(mapB, key)})
+	}
+	_ = w.Flush()
+
+	var notes []string
+	if len(missingA) > 0 {
+		notes = append(notes, tr.T(msgCompareMissing, nameA, strings.Join(missingA, ", ")))
+	}
+	if len(missingB) > 0 {
+		notes = append(notes, tr.T(msgCompareMissing, nameB, strings.Join(missingB, ", ")))
+	}
+
+	lines := strings.SplitAfterN(buf.String(), "\n", 2)
+	if len(lines) < 2 {
+		return buf.String(), notes
+	}
+	return lines[0] + tableRule(lines[0]) + lines[1], notes
+}
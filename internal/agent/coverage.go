@@ -0,0 +1,43 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// coverageNote flags a forecast that is shorter than requested or skips a
+// date, logging a warning for each problem. It returns "" when the dates
+// cover the requested window without gaps.
+func (a *Agent) coverageNote(check string, requested int, dates []time.Time) string {
+	var notes []string
+	if len(dates) < requested {
+		fmt.Printf("warning: %s forecast has %d of %d requested days\n", check, len(dates), requested)
+		notes = append(notes, a.tr.T(msgShortForecast, len(dates), requested))
+	}
+	for i := 1; i < len(dates); i++ {
+		for d := dates[i-1].AddDate(0, 0, 1); d.Before(dates[i]); d = d.AddDate(0, 0, 1) {
+			fmt.Printf("warning: %s forecast is missing %s\n", check, d.Format("2006-01-02"))
+			notes = append(notes, a.tr.T(msgMissingDay, a.tr.Day(d)))
+		}
+	}
+	return strings.Join(notes, "\n")
+}
+
+func forecastDates(days []weather.ForecastDay) []time.Time {
+	dates := make([]time.Time, len(days))
+	for i, d := range days {
+		dates[i] = d.Date
+	}
+	return dates
+}
+
+func rainDates(days []weather.RainForecast) []time.Time {
+	dates := make([]time.Time, len(days))
+	for i, d := range days {
+		dates[i] = d.Date
+	}
+	return dates
+}
@@ -0,0 +1,53 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// daytimeEasterlyLine names the days within the confidence horizon whose
+// wind during DaytimeHours is easterly, e.g. "Easterly during daytime
+// (08–20): Tue 14 Jan, Wed 15 Jan". Each day uses the speed-weighted mean
+// direction of its hourly winds in the window, so a morning easterly that
+// swings west by nine doesn't count, and MinEasterlySpeed applies to the
+// strongest of those hours. Returns "" when DaytimeHours is unset.
+func (a *Agent) daytimeEasterlyLine(ctx context.Context, days []weather.ForecastDay) (string, error) {
+	start, end := a.cfg.DaytimeHours[0], a.cfg.DaytimeHours[1]
+	if start == end || len(days) == 0 {
+		return "", nil
+	}
+	hourly, err := a.cfg.WindWeather.FetchHourlyWind(ctx, len(days))
+	if err != nil {
+		return "", fmt.Errorf("fetch hourly wind for daytime hours: %w", err)
+	}
+	byDay := weather.GroupByDay(hourly)
+
+	var easterly []string
+	for _, d := range days {
+		if a.beyondHorizon(d) {
+			break
+		}
+		var hours []weather.HourlyWind
+		peak := 0.0
+		for _, h := range byDay[d.Date.Format("2006-01-02")] {
+			if h.Time.Hour() >= start && h.Time.Hour() < end {
+				hours = append(hours, h)
+				peak = max(peak, h.Speed)
+			}
+		}
+		if len(hours) == 0 {
+			continue
+		}
+		minSpeed := a.cfg.MinEasterlySpeed
+		if isEasterly(weather.DominantDirection(hours)) && (minSpeed <= 0 || peak > minSpeed) {
+			easterly = append(easterly, a.tr.Day(d.Date))
+		}
+	}
+	if len(easterly) == 0 {
+		return a.tr.T(msgDaytimeNone, start, end), nil
+	}
+	return withMarker(a.tr.T(msgDaytimeEasterly, start, end, strings.Join(easterly, ", ")), a.cfg.Theme.Easterly), nil
+}
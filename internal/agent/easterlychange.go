@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// easterlyChanges compares the easterly days of two forecasts. added are
+// easterly now but weren't before, including days new to the window;
+// dropped were easterly before but no longer are. Days only one forecast
+// covers can't have dropped, and days before curr starts are ignored.
+func easterlyChanges(prev, curr []weather.ForecastDay, minSpeed float64) (added, dropped []time.Time) {
+	wasEasterly := make(map[string]bool, len(prev))
+	for _, d := range prev {
+		wasEasterly[d.Date.Format(time.DateOnly)] = dayEasterly(d, minSpeed)
+	}
+	for _, d := range curr {
+		key := d.Date.Format(time.DateOnly)
+		switch now := dayEasterly(d, minSpeed); {
+		case now && !wasEasterly[key]:
+			added = append(added, d.Date)
+		case !now && wasEasterly[key]:
+			dropped = append(dropped, d.Date)
+		}
+	}
+	return added, dropped
+}
+
+// easterlyChangeReport sends EasterlyChangesOnly's report, and nothing when
+// the forecast's easterly days are the same as last time. On the first run
+// every easterly day is new.
+func (a *Agent) easterlyChangeReport(ctx context.Context, forecast []weather.ForecastDay, fetchedAt time.Time) error {
+	a.logWind(ctx, fetchedAt, forecast)
+	prev := a.swapLastForecast(forecast)
+	added, dropped := easterlyChanges(prev, a.reliableDays(forecast), a.cfg.MinEasterlySpeed)
+	if len(added) == 0 && len(dropped) == 0 {
+		fmt.Println("no change in easterly days, not notifying")
+		return nil
+	}
+
+	days := func(dates []time.Time) string {
+		names := make([]string, len(dates))
+		for i, d := range dates {
+			names[i] = a.tr.Day(d)
+		}
+		return strings.Join(names, ", ")
+	}
+	var lines []string
+	if len(added) > 0 {
+		lines = append(lines, withMarker(a.tr.T(msgNewEasterly, days(added)), a.cfg.Theme.Easterly))
+	}
+	if len(dropped) > 0 {
+		lines = append(lines, a.tr.T(msgEasterlyDropped, days(dropped)))
+	}
+	r := Report{
+		Kind:     checkWind,
+		Location: a.cfg.WindLocation,
+		Headline: strings.Join(lines, "\n"),
+		Footer:   a.issuedFooter(fetchedAt),
+		IssuedAt: fetchedAt,
+	}
+	fmt.Printf("\n🛫 %s easterly changes:\n%s\n", a.cfg.WindLocation, r.Headline)
+	a.writeSink(fetchedAt, a.cfg.WindLocation+" wind", r)
+	a.writeMarkdown(r)
+	if err := a.deliver(ctx, r); err != nil {
+		return fmt.Errorf("wind notify: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// EmailNotifier sends reports as plain-text email over SMTP.
+type EmailNotifier struct {
+	Addr     string // host:port
+	From     string
+	To       []string
+	Username string // optional; enables PLAIN auth
+	Password string
+}
+
+// Name implements Notifier.
+func (e *EmailNotifier) Name() string { return "email" }
+
+// Notify implements Notifier. SMTP has no context support, so ctx is only
+// checked before sending.
+func (e *EmailNotifier) Notify(ctx context.Context, r Report) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	subject := fmt.Sprintf("%s %s forecast", r.Location, r.Kind)
+	if r.Kind == "alert" {
+		subject = fmt.Sprintf("%s weather alert", r.Location)
+	}
+	msg := "From: " + e.From + "\r\n" +
+		"To: " + strings.Join(e.To, ", ") + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n\r\n" +
+		strings.ReplaceAll(r.PlainText(), "\n", "\r\n") + "\r\n"
+
+	var auth smtp.Auth
+	if e.Username != "" {
+		host, _, err := net.SplitHostPort(e.Addr)
+		if err != nil {
+			return fmt.Errorf("parse smtp address: %w", err)
+		}
+		auth = smtp.PlainAuth("", e.Username, e.Password, host)
+	}
+	if err := smtp.SendMail(e.Addr, auth, e.From, e.To, []byte(msg)); err != nil {
+		return fmt.Errorf("send mail: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// extraWindReports fetches ExtraWindLocations in parallel and sends each a
+// table-only wind report as soon as its forecast arrives. Failures are
+// collected per location.
+func (a *Agent) extraWindReports(ctx context.Context, at time.Time) error {
+	if len(a.cfg.ExtraWindLocations) == 0 {
+		return nil
+	}
+	locations := make([]weather.NamedForecaster, len(a.cfg.ExtraWindLocations))
+	for i, l := range a.cfg.ExtraWindLocations {
+		c := *a.cfg.WindWeather
+		c.Latitude, c.Longitude = l.Latitude, l.Longitude
+		locations[i] = weather.NamedForecaster{Name: l.Name, Forecaster: &c}
+	}
+
+	var errs []error
+	weather.FetchMany(ctx, locations, a.cfg.WindDays, a.cfg.FetchConcurrency, func(res weather.LocationResult) {
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("%s wind forecast: %w", res.Name, res.Err))
+			return
+		}
+		r := Report{
+			Kind:     checkWind,
+			Location: res.Name,
+			Headline: strings.TrimRight(res.Name+"\n"+buildEasterlyAnalysis(a.reliableDays(res.Days), a.cfg.DominantMargin, a.cfg.MinEasterlySpeed, a.cfg.Theme.Easterly, a.tr), "\n"),
+			Table:    a.buildForecastTable(res.Days),
+			Footer:   a.issuedFooter(at),
+			IssuedAt: at,
+		}
+		a.writeSink(at, res.Name+" wind", r)
+		a.writeMarkdown(r)
+		if err := a.deliver(ctx, r); err != nil {
+			errs = append(errs, fmt.Errorf("%s wind notify: %w", res.Name, err))
+		}
+	})
+	return errors.Join(errs...)
+}
@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileSink appends each rendered report to a local log file. When the file
+// grows beyond MaxBytes it is rotated to Path+".1" (replacing any previous
+// rotation). Safe for concurrent use.
+type FileSink struct {
+	Path     string
+	MaxBytes int64 // zero disables rotation
+	// Renderer lays out each entry. Defaults to ConsoleRenderer.
+	Renderer Renderer
+
+	mu sync.Mutex
+}
+
+// Write renders r and appends it under a timestamped header.
+func (s *FileSink) Write(at time.Time, title string, r Report) error {
+	renderer := s.Renderer
+	if renderer == nil {
+		renderer = ConsoleRenderer{}
+	}
+	var report strings.Builder
+	if err := renderer.Render(&report, r); err != nil {
+		return fmt.Errorf("render report log entry: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.rotate(); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open report log: %w", err)
+	}
+	entry := fmt.Sprintf("=== %s %s ===\n%s\n\n", at.UTC().Format(time.RFC3339), title, strings.TrimRight(report.String(), "\n"))
+	// One Write call per entry keeps entries whole even across processes.
+	if _, err := f.WriteString(entry); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write report log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close report log: %w", err)
+	}
+	return nil
+}
+
+func (s *FileSink) rotate() error {
+	if s.MaxBytes <= 0 {
+		return nil
+	}
+	info, err := os.Stat(s.Path)
+	if err != nil || info.Size() < s.MaxBytes {
+		return nil
+	}
+	if err := os.Rename(s.Path, s.Path+".1"); err != nil {
+		return fmt.Errorf("rotate report log: %w", err)
+	}
+	return nil
+}
+
+// writeSink records a report in the file sink, if configured. Failures are
+// logged but never fail the run.
+func (a *Agent) writeSink(at time.Time, title string, r Report) {
+	if a.cfg.FileSink == nil {
+		return
+	}
+	if err := a.cfg.FileSink.Write(at, title, r); err != nil {
+		fmt.Printf("warning: %v\n", err)
+	}
+}
@@ -0,0 +1,180 @@
+package agent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// RunLog records the forecasts fetched by each run, for later analysis.
+type RunLog interface {
+	LogWind(ctx context.Context, fetchedAt time.Time, days []weather.ForecastDay) error
+	LogRain(ctx context.Context, fetchedAt time.Time, days []weather.RainForecast) error
+}
+
+// StoredForecast is one day of a logged wind forecast.
+type StoredForecast struct {
+	FetchedAt time.Time
+	// Day has the logged Date, WindSpeedMax, WindGustMax and direction.
+	Day weather.ForecastDay
+}
+
+// WindHistory is implemented by run logs that can read wind forecasts back,
+// as Config.AccuracyReport needs.
+type WindHistory interface {
+	// WindForecasts returns every logged forecast day dated on or after
+	// since, ordered by fetch time and date.
+	WindForecasts(ctx context.Context, since time.Time) ([]StoredForecast, error)
+}
+
+// SQLRunLog stores forecasts in a SQL database, one row per fetch time and
+// date. The schema targets SQLite (e.g. the pure-Go modernc.org/sqlite
+// driver, registered as "sqlite") and is created on first use.
+type SQLRunLog struct {
+	DB *sql.DB
+
+	once    sync.Once
+	initErr error
+}
+
+const runLogSchema = `
+CREATE TABLE IF NOT EXISTS wind_forecasts (
+	fetched_at     TEXT NOT NULL,
+	date           TEXT NOT NULL,
+	wind_speed_max REAL NOT NULL,
+	wind_gust_max  REAL NOT NULL,
+	wind_dir_mean  REAL,
+	temp_max       REAL,
+	temp_min       REAL,
+	weather_code   INTEGER,
+	PRIMARY KEY (fetched_at, date)
+);
+CREATE TABLE IF NOT EXISTS rain_forecasts (
+	fetched_at  TEXT NOT NULL,
+	date        TEXT NOT NULL,
+	precip_prob INTEGER NOT NULL,
+	precip      REAL NOT NULL,
+	unit        TEXT NOT NULL,
+	PRIMARY KEY (fetched_at, date)
+);`
+
+func (l *SQLRunLog) init(ctx context.Context) error {
+	l.once.Do(func() {
+		if _, err := l.DB.ExecContext(ctx, runLogSchema); err != nil {
+			l.initErr = fmt.Errorf("create run log schema: %w", err)
+		}
+	})
+	return l.initErr
+}
+
+// LogWind implements RunLog.
+func (l *SQLRunLog) LogWind(ctx context.Context, fetchedAt time.Time, days []weather.ForecastDay) error {
+	if err := l.init(ctx); err != nil {
+		return err
+	}
+	return l.inTx(ctx, `INSERT OR REPLACE INTO wind_forecasts
+		(fetched_at, date, wind_speed_max, wind_gust_max, wind_dir_mean, temp_max, temp_min, weather_code)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, len(days), func(i int) []any {
+		d := days[i]
+		dir := sql.NullFloat64{Float64: d.WindDirMean, Valid: !d.DirUnknown}
+		return []any{fetchedAt.UTC().Format(time.RFC3339), d.Date.Format("2006-01-02"),
+			d.WindSpeedMax, d.WindGustMax, dir, d.TempMax, d.TempMin, d.WeatherCode}
+	})
+}
+
+// WindForecasts implements WindHistory.
+func (l *SQLRunLog) WindForecasts(ctx context.Context, since time.Time) ([]StoredForecast, error) {
+	if err := l.init(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := l.DB.QueryContext(ctx, `SELECT fetched_at, date, wind_speed_max, wind_gust_max, wind_dir_mean
+		FROM wind_forecasts WHERE date >= ? ORDER BY fetched_at, date`, since.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("query run log: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []StoredForecast
+	for rows.Next() {
+		var fetchedAt, date string
+		var f StoredForecast
+		var dir sql.NullFloat64
+		if err := rows.Scan(&fetchedAt, &date, &f.Day.WindSpeedMax, &f.Day.WindGustMax, &dir); err != nil {
+			return nil, fmt.Errorf("scan run log: %w", err)
+		}
+		if f.FetchedAt, err = time.Parse(time.RFC3339, fetchedAt); err != nil {
+			return nil, fmt.Errorf("parse run log fetch time %q: %w", fetchedAt, err)
+		}
+		if f.Day.Date, err = time.Parse("2006-01-02", date); err != nil {
+			return nil, fmt.Errorf("parse run log date %q: %w", date, err)
+		}
+		f.Day.WindDirMean, f.Day.DirUnknown = dir.Float64, !dir.Valid
+		out = append(out, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read run log: %w", err)
+	}
+	return out, nil
+}
+
+// LogRain implements RunLog.
+func (l *SQLRunLog) LogRain(ctx context.Context, fetchedAt time.Time, days []weather.RainForecast) error {
+	if err := l.init(ctx); err != nil {
+		return err
+	}
+	return l.inTx(ctx, `INSERT OR REPLACE INTO rain_forecasts
+		(fetched_at, date, precip_prob, precip, unit)
+		VALUES (?, ?, ?, ?, ?)`, len(days), func(i int) []any {
+		d := days[i]
+		return []any{fetchedAt.UTC().Format(time.RFC3339), d.Date.Format("2006-01-02"),
+			d.PrecipProb, d.PrecipMM, d.UnitSymbol()}
+	})
+}
+
+// inTx runs query once per row inside a single transaction.
+func (l *SQLRunLog) inTx(ctx context.Context, query string, rows int, args func(int) []any) error {
+	tx, err := l.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin run log: %w", err)
+	}
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return errors.Join(fmt.Errorf("prepare run log: %w", err), tx.Rollback())
+	}
+	defer func() { _ = stmt.Close() }()
+	for i := 0; i < rows; i++ {
+		if _, err := stmt.ExecContext(ctx, args(i)...); err != nil {
+			return errors.Join(fmt.Errorf("insert run log: %w", err), tx.Rollback())
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit run log: %w", err)
+	}
+	return nil
+}
+
+// logWind records a wind forecast in the run log, if configured. Failures are
+// logged but never fail the run.
+func (a *Agent) logWind(ctx context.Context, at time.Time, days []weather.ForecastDay) {
+	if a.cfg.RunLog == nil {
+		return
+	}
+	if err := a.cfg.RunLog.LogWind(ctx, at, days); err != nil {
+		fmt.Printf("warning: %v\n", err)
+	}
+}
+
+// logRain records a rain forecast in the run log, if configured.
+func (a *Agent) logRain(ctx context.Context, at time.Time, days []weather.RainForecast) {
+	if a.cfg.RunLog == nil {
+		return
+	}
+	if err := a.cfg.RunLog.LogRain(ctx, at, days); err != nil {
+		fmt.Printf("warning: %v\n", err)
+	}
+}
@@ -0,0 +1,49 @@
+package agent
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+	_ "modernc.org/sqlite"
+)
+
+func TestSQLRunLogRoundTrip(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	l := &SQLRunLog{DB: db}
+	ctx := context.Background()
+
+	days := testDays(t)
+	if err := l.LogWind(ctx, testNow, days); err != nil {
+		t.Fatalf("LogWind: %v", err)
+	}
+	// Logging the same fetch again replaces its rows.
+	if err := l.LogWind(ctx, testNow, days); err != nil {
+		t.Fatalf("LogWind again: %v", err)
+	}
+	got, err := l.WindForecasts(ctx, days[1].Date)
+	if err != nil {
+		t.Fatalf("WindForecasts: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d stored days, want 2", len(got))
+	}
+	if !got[0].FetchedAt.Equal(testNow) || !got[0].Day.Date.Equal(days[1].Date) || got[0].Day.WindSpeedMax != days[1].WindSpeedMax {
+		t.Errorf("first stored day = %+v, want %v fetched at %s", got[0], days[1], testNow)
+	}
+
+	rain := []weather.RainForecast{{Date: days[0].Date, PrecipProb: 40, PrecipMM: 1.5, Unit: "mm"}}
+	if err := l.LogRain(ctx, testNow, rain); err != nil {
+		t.Fatalf("LogRain: %v", err)
+	}
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM rain_forecasts`).Scan(&n); err != nil || n != 1 {
+		t.Errorf("stored %d rain rows (%v), want 1", n, err)
+	}
+}
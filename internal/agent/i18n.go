@@ -0,0 +1,395 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// msgKey identifies a phrase generated by the agent itself (never the Ollama
+// output, which is left untouched).
+type msgKey int
+
+const (
+	msgColDate msgKey = iota
+	msgColWind
+	msgColDir
+	msgColEast
+	msgColTemp
+	msgColGust
+	msgRainHeader
+	msgDominant
+	msgMixed
+	msgMostly
+	msgEast
+	msgWest
+	msgNoData
+	msgSparkline
+	msgWeekend
+	msgDropOff
+	msgPickup
+	msgUmbrella
+	msgMaybeUmbrella
+	msgWeekLine
+	msgWeekEasterly
+	msgLongestStreak
+	msgLongestStreakOne
+	msgToday
+	msgAirQuality
+	msgChanges
+	msgAllClear
+	msgShortForecast
+	msgTimelineAll
+	msgBeyondHorizon
+	msgWeekendsCalmer
+	msgWeekendsWindier
+	msgWeekendsSimilar
+	msgTimelineUntil
+	msgTimelineThen
+	msgTimelineBack
+	msgMissingDay
+	msgWindUp
+	msgWindDown
+	msgPollen
+	msgTomorrow
+	msgIssued
+	msgMorningPrecip
+	msgNow
+	msgVariable
+	msgCompareEasterly
+	msgCompareWesterly
+	msgCompareNoData
+	msgCompareMissing
+	msgWindiest
+	msgDawnWind
+	msgDuskWind
+	msgSunWindDay
+	msgSunWindNone
+	msgCalm
+	msgLight
+	msgModerate
+	msgStrong
+	msgRainTiming
+	msgRainTimingDay
+	msgTimingMorning
+	msgTimingAfternoon
+	msgTimingEvening
+	msgTimingAllDay
+	msgTimingScattered
+	msgVeering
+	msgBacking
+	msgAccuracyTitle
+	msgAccuracyNone
+	msgColLead
+	msgColSamples
+	msgColMAE
+	msgColDirHits
+	msgNewEasterly
+	msgEasterlyDropped
+	msgColTempRange
+	msgDaytimeEasterly
+	msgDaytimeNone
+	msgOllamaOffline
+	msgRunwayEasterly
+	msgRunwayWesterly
+	msgRunwayEither
+	msgStableEasterly
+	msgStableWesterly
+	msgCommandHelp
+	msgCommandDays
+	msgCommandBusy
+	msgCommandFailed
+)
+
+// catalogs holds the translations per language. English is the reference and
+// the fallback for any key missing from another catalog.
+var catalogs = map[string]map[msgKey]string{
+	"en": {
+		msgColDate:          "Date",
+		msgColWind:          "Wind",
+		msgColDir:           "Dir",
+		msgColEast:          "East",
+		msgColTemp:          "Temp",
+		msgColGust:          "Gust",
+		msgRainHeader:       "Date       | Drop | Pick\n-----------+------+------\n",
+		msgDominant:         "Dominant: %s | East: %d days (%d%%) | West: %d days (%d%%)\n",
+		msgMixed:            "Mixed",
+		msgMostly:           "Mostly %s, some %s",
+		msgEast:             "E",
+		msgWest:             "W",
+		msgNoData:           "No forecast data",
+		msgSparkline:        "Wind: %s",
+		msgWeekend:          "📅 Weekend - no school!",
+		msgDropOff:          "DROP-OFF (8-9am)",
+		msgPickup:           "PICKUP (%s)",
+		msgUmbrella:         "Umbrella!",
+		msgMaybeUmbrella:    "Maybe umbrella",
+		msgWeekLine:         "Week %d: mostly %s, gusts to %.0f",
+		msgWeekEasterly:     "; easterly %s",
+		msgLongestStreak:    "Longest easterly streak: %s, %d days",
+		msgLongestStreakOne: "Longest easterly streak: %s, %d day",
+		msgToday:            "Today",
+		msgAirQuality:       "🌬️ Air: %s (AQI %d), PM2.5 %.0f, PM10 %.0f",
+		msgPollen:           "; pollen grass %.0f, tree %.0f",
+		msgChanges:          "Changes since last forecast:",
+		msgAllClear:         "✅ Nothing notable today — wind up to %.0f %s, %s",
+		msgShortForecast:    "⚠️ Only %d of %d days available",
+		msgTimelineAll:      "%s throughout",
+		msgBeyondHorizon:    "? beyond reliable range (%d+ days ahead)",
+		msgWeekendsCalmer:   "Weekends calmer (avg %.0f vs %.0f %s; easterly %d%% vs %d%%)",
+		msgWeekendsWindier:  "Weekends windier (avg %.0f vs %.0f %s; easterly %d%% vs %d%%)",
+		msgWeekendsSimilar:  "Weekends like weekdays (avg %.0f vs %.0f %s; easterly %d%% vs %d%%)",
+		msgTimelineUntil:    "%s until %s",
+		msgTimelineThen:     "then %s %s",
+		msgTimelineBack:     "back to %s %s",
+		msgMissingDay:       "⚠️ No data for %s",
+		msgWindUp:           "wind up %.0f %s",
+		msgWindDown:         "wind down %.0f %s",
+		msgTomorrow:         "Tomorrow",
+		msgIssued:           "Forecast issued %s (Open-Meteo)",
+		msgMorningPrecip:    "🌨️ Morning: %s (%s)",
+		msgNow:              "Now: %.0f%s, %s %.0f %s, %s",
+		msgVariable:         "Variable winds (~): %s",
+		msgCompareEasterly:  "%s easterly %s",
+		msgCompareWesterly:  "%s westerly all period",
+		msgCompareNoData:    "%s: no forecast data",
+		msgCompareMissing:   "⚠️ %s has no data for %s",
+		msgWindiest:         "Windiest: %s (gusts %.0f %s)",
+		msgDawnWind:         "Dawn wind (%s):",
+		msgDuskWind:         "Dusk wind (%s):",
+		msgSunWindDay:       "%s %s: %s %s %.0f %s",
+		msgSunWindNone:      "%s: no %s",
+		msgCalm:             "calm",
+		msgLight:            "light",
+		msgModerate:         "moderate",
+		msgStrong:           "strong",
+		msgRainTiming:       "Rain timing:",
+		msgRainTimingDay:    "%s: %s (%s)",
+		msgTimingMorning:    "rain in the morning, clearing by midday",
+		msgTimingAfternoon:  "rain in the afternoon",
+		msgTimingEvening:    "rain in the evening",
+		msgTimingAllDay:     "rain on and off all day",
+		msgTimingScattered:  "scattered showers",
+		msgVeering:          "⚠ Wind veering sharply %s→%s (%.0f°→%.0f°, %.0f°)",
+		msgBacking:          "⚠ Wind backing sharply %s→%s (%.0f°→%.0f°, %.0f°)",
+		msgAccuracyTitle:    "📏 Forecast accuracy over the last %d days, against Open-Meteo's past days",
+		msgAccuracyNone:     "No logged forecasts can be checked yet",
+		msgColLead:          "Ahead",
+		msgColSamples:       "Days",
+		msgColMAE:           "Wind err",
+		msgColDirHits:       "E/W right",
+		msgNewEasterly:      "New easterly forecast: %s",
+		msgEasterlyDropped:  "No longer easterly: %s",
+		msgColTempRange:     "Temp range",
+		msgDaytimeEasterly:  "Easterly during daytime (%02d–%02d): %s",
+		msgDaytimeNone:      "No easterly during daytime (%02d–%02d)",
+		msgOllamaOffline:    "⚠️ No summary: Ollama unreachable at %s — is it running?",
+		msgRunwayEasterly:   "%s: landing on %s — easterly ops",
+		msgRunwayWesterly:   "%s: landing on %s — westerly ops",
+		msgRunwayEither:     "%s: landing on %s or %s — light easterly, could go either way",
+		msgStableEasterly:   "Stable easterly through %s — expect planes overhead",
+		msgStableWesterly:   "Stable westerly through %s — no action needed",
+		msgCommandHelp:      "/forecast — the %[1]d-day wind forecast\n/forecast N — the next N days (1-%[1]d)",
+		msgCommandDays:      "Ask for 1 to %d days, e.g. /forecast 7",
+		msgCommandBusy:      "A forecast is already on its way, try again in a moment",
+		msgCommandFailed:    "⚠️ Couldn't fetch the forecast, try again later",
+	},
+	"it": {
+		msgColDate:          "Data",
+		msgColWind:          "Vent",
+		msgColDir:           "Dir",
+		msgColEast:          "Est",
+		msgColTemp:          "Temp",
+		msgColGust:          "Raff",
+		msgRainHeader:       "Data       | Entr | Usc\n-----------+------+------\n",
+		msgDominant:         "Prevalente: %s | Est: %d giorni (%d%%) | Ovest: %d giorni (%d%%)\n",
+		msgMixed:            "Misto",
+		msgMostly:           "Perlopiù %s, un po' %s",
+		msgEast:             "E",
+		msgWest:             "O",
+		msgNoData:           "Nessun dato di previsione",
+		msgSparkline:        "Vento: %s",
+		msgWeekend:          "📅 Weekend - niente scuola!",
+		msgDropOff:          "ENTRATA (8-9)",
+		msgPickup:           "USCITA (%s)",
+		msgUmbrella:         "Ombrello!",
+		msgMaybeUmbrella:    "Forse ombrello",
+		msgWeekLine:         "Settimana %d: prevalente %s, raffiche fino a %.0f",
+		msgWeekEasterly:     "; vento da est %s",
+		msgLongestStreak:    "Serie più lunga di vento da est: %s, %d giorni",
+		msgLongestStreakOne: "Serie più lunga di vento da est: %s, %d giorno",
+		msgToday:            "Oggi",
+		msgAirQuality:       "🌬️ Aria: %s (AQI %d), PM2.5 %.0f, PM10 %.0f",
+		msgPollen:           "; pollini graminacee %.0f, alberi %.0f",
+		msgChanges:          "Cambiamenti dall'ultima previsione:",
+		msgAllClear:         "✅ Niente da segnalare oggi — vento fino a %.0f %s, %s",
+		msgShortForecast:    "⚠️ Solo %d giorni disponibili su %d",
+		msgTimelineAll:      "%s per tutto il periodo",
+		msgBeyondHorizon:    "? oltre il limite di affidabilità (da %d giorni in avanti)",
+		msgWeekendsCalmer:   "Weekend più calmi (media %.0f contro %.0f %s; da est %d%% contro %d%%)",
+		msgWeekendsWindier:  "Weekend più ventosi (media %.0f contro %.0f %s; da est %d%% contro %d%%)",
+		msgWeekendsSimilar:  "Weekend come i giorni feriali (media %.0f contro %.0f %s; da est %d%% contro %d%%)",
+		msgTimelineUntil:    "%s fino a %s",
+		msgTimelineThen:     "poi %s %s",
+		msgTimelineBack:     "di nuovo %s %s",
+		msgMissingDay:       "⚠️ Nessun dato per %s",
+		msgWindUp:           "vento +%.0f %s",
+		msgWindDown:         "vento -%.0f %s",
+		msgTomorrow:         "Domani",
+		msgIssued:           "Previsione emessa %s (Open-Meteo)",
+		msgMorningPrecip:    "🌨️ Mattina: %s (%s)",
+		msgNow:              "Ora: %.0f%s, %s %.0f %s, %s",
+		msgVariable:         "Vento variabile (~): %s",
+		msgCompareEasterly:  "%s vento da est %s",
+		msgCompareWesterly:  "%s vento da ovest per tutto il periodo",
+		msgCompareNoData:    "%s: nessun dato di previsione",
+		msgCompareMissing:   "⚠️ %s non ha dati per %s",
+		msgWindiest:         "Più ventoso: %s (raffiche %.0f %s)",
+		msgDawnWind:         "Vento all'alba (%s):",
+		msgDuskWind:         "Vento al tramonto (%s):",
+		msgSunWindDay:       "%s %s: %s %s %.0f %s",
+		msgSunWindNone:      "%s: nessun %s",
+		msgCalm:             "calmo",
+		msgLight:            "debole",
+		msgModerate:         "moderato",
+		msgStrong:           "forte",
+		msgRainTiming:       "Quando piove:",
+		msgRainTimingDay:    "%s: %s (%s)",
+		msgTimingMorning:    "pioggia al mattino, smette entro mezzogiorno",
+		msgTimingAfternoon:  "pioggia nel pomeriggio",
+		msgTimingEvening:    "pioggia in serata",
+		msgTimingAllDay:     "pioggia a tratti tutto il giorno",
+		msgTimingScattered:  "rovesci sparsi",
+		msgVeering:          "⚠ Vento che ruota bruscamente in senso orario %s→%s (%.0f°→%.0f°, %.0f°)",
+		msgBacking:          "⚠ Vento che ruota bruscamente in senso antiorario %s→%s (%.0f°→%.0f°, %.0f°)",
+		msgAccuracyTitle:    "📏 Affidabilità delle previsioni negli ultimi %d giorni, rispetto ai giorni passati di Open-Meteo",
+		msgAccuracyNone:     "Nessuna previsione registrata è ancora verificabile",
+		msgColLead:          "Anticipo",
+		msgColSamples:       "Giorni",
+		msgColMAE:           "Err. vento",
+		msgColDirHits:       "E/O giusti",
+		msgNewEasterly:      "Nuovo vento da est previsto: %s",
+		msgEasterlyDropped:  "Non più da est: %s",
+		msgColTempRange:     "Escursione",
+		msgDaytimeEasterly:  "Vento da est di giorno (%02d–%02d): %s",
+		msgDaytimeNone:      "Nessun vento da est di giorno (%02d–%02d)",
+		msgOllamaOffline:    "⚠️ Nessun riepilogo: Ollama non raggiungibile su %s — è in esecuzione?",
+		msgRunwayEasterly:   "%s: atterraggi su %s — operazioni verso est",
+		msgRunwayWesterly:   "%s: atterraggi su %s — operazioni verso ovest",
+		msgRunwayEither:     "%s: atterraggi su %s o %s — vento debole da est, incerto",
+		msgStableEasterly:   "Vento da est stabile fino a %s — aerei sopra la testa",
+		msgStableWesterly:   "Vento da ovest stabile fino a %s — nulla da fare",
+		msgCommandHelp:      "/forecast — previsioni del vento a %[1]d giorni\n/forecast N — i prossimi N giorni (1-%[1]d)",
+		msgCommandDays:      "Chiedi da 1 a %d giorni, es. /forecast 7",
+		msgCommandBusy:      "Una previsione è già in arrivo, riprova tra poco",
+		msgCommandFailed:    "⚠️ Impossibile ottenere le previsioni, riprova più tardi",
+	},
+}
+
+var (
+	weekdayNames = map[string][7]string{
+		"en": {"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		"it": {"Dom", "Lun", "Mar", "Mer", "Gio", "Ven", "Sab"},
+	}
+	monthNames = map[string][12]string{
+		"en": {"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		"it": {"Gen", "Feb", "Mar", "Apr", "Mag", "Giu", "Lug", "Ago", "Set", "Ott", "Nov", "Dic"},
+	}
+)
+
+// precipNames translates weather.PrecipType values. English uses the type's
+// own string value.
+var precipNames = map[string]map[weather.PrecipType]string{
+	"it": {
+		weather.PrecipDry:           "asciutto",
+		weather.PrecipSteadyRain:    "pioggia continua",
+		weather.PrecipShowers:       "rovesci",
+		weather.PrecipSnow:          "neve",
+		weather.PrecipSleet:         "nevischio",
+		weather.PrecipWintryShowers: "rovesci nevosi",
+	},
+}
+
+// aqiNames translates weather.AQICategory bands. English uses the band as is.
+var aqiNames = map[string]map[string]string{
+	"it": {
+		"good": "buona", "fair": "discreta", "moderate": "moderata",
+		"poor": "scadente", "very poor": "molto scadente", "extremely poor": "pessima",
+	},
+}
+
+// weatherCodeNames translates WMO weather codes. English falls back to
+// weather.WeatherCodeDescription.
+var weatherCodeNames = map[string]map[int]string{
+	"it": {
+		0: "sereno", 1: "prevalentemente sereno", 2: "parzialmente nuvoloso", 3: "coperto",
+		45: "nebbia", 48: "nebbia gelata",
+		51: "pioviggine leggera", 53: "pioviggine", 55: "pioviggine intensa",
+		56: "pioviggine gelata", 57: "pioviggine gelata intensa",
+		61: "pioggia leggera", 63: "pioggia", 65: "pioggia forte",
+		66: "pioggia gelata", 67: "pioggia gelata forte",
+		71: "neve leggera", 73: "neve", 75: "neve forte", 77: "granuli di neve",
+		80: "rovesci leggeri", 81: "rovesci", 82: "rovesci violenti",
+		85: "rovesci di neve", 86: "forti rovesci di neve",
+		95: "temporale", 96: "temporale con grandine", 99: "temporale con forte grandine",
+	},
+}
+
+// translator renders agent phrases in a single language.
+type translator struct {
+	lang string
+}
+
+// newTranslator returns a translator for lang, falling back to English for
+// unknown languages.
+func newTranslator(lang string) translator {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if _, ok := catalogs[lang]; !ok {
+		lang = "en"
+	}
+	return translator{lang: lang}
+}
+
+// T looks up key and, when args are given, formats it like fmt.Sprintf.
+func (t translator) T(key msgKey, args ...any) string {
+	s, ok := catalogs[t.lang][key]
+	if !ok {
+		s = catalogs["en"][key]
+	}
+	if len(args) == 0 {
+		return s
+	}
+	return fmt.Sprintf(s, args...)
+}
+
+// Precip returns the localized name of a precipitation type.
+func (t translator) Precip(kind weather.PrecipType) string {
+	if name, ok := precipNames[t.lang][kind]; ok {
+		return name
+	}
+	return string(kind)
+}
+
+// AQI returns the localized European AQI band for aqi.
+func (t translator) AQI(aqi int) string {
+	band := weather.AQICategory(aqi)
+	if name, ok := aqiNames[t.lang][band]; ok {
+		return name
+	}
+	return band
+}
+
+// WeatherCode returns the localized description of a WMO weather code.
+func (t translator) WeatherCode(code int) string {
+	if name, ok := weatherCodeNames[t.lang][code]; ok {
+		return name
+	}
+	return weather.WeatherCodeDescription(code)
+}
+
+// Day formats a date as "Mon 02 Jan" in the translator's language.
+func (t translator) Day(d time.Time) string {
+	return fmt.Sprintf("%s %02d %s", weekdayNames[t.lang][d.Weekday()], d.Day(), monthNames[t.lang][d.Month()-1])
+}
@@ -0,0 +1,149 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
+)
+
+// locationsSettle is how long WatchLocations waits after the last change to
+// the file before reloading it.
+const locationsSettle = 100 * time.Millisecond
+
+// Location is a named point to forecast for.
+type Location struct {
+	Name      string  `yaml:"name"`
+	Latitude  float64 `yaml:"lat"`
+	Longitude float64 `yaml:"lon"`
+}
+
+// Locations is the content of a YAML locations file, such as
+//
+//	wind:
+//	  name: Heathrow
+//	  lat: 51.47
+//	  lon: -0.45
+//
+// A nil entry leaves that
+// check's current location unchanged.
+type Locations struct {
+	Wind *Location `yaml:"wind"`
+	Rain *Location `yaml:"rain"`
+}
+
+// LoadLocations reads and validates a YAML locations file. JSON, being
+// valid YAML, is accepted too.
+func LoadLocations(path string) (Locations, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Locations{}, fmt.Errorf("read locations: %w", err)
+	}
+	var locs Locations
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&locs); err != nil && !errors.Is(err, io.EOF) {
+		return Locations{}, fmt.Errorf("decode locations: %w", err)
+	}
+	for check, l := range map[string]*Location{checkWind: locs.Wind, checkRain: locs.Rain} {
+		if l == nil {
+			continue
+		}
+		if err := l.validate(); err != nil {
+			return Locations{}, fmt.Errorf("%s location: %w", check, err)
+		}
+	}
+	return locs, nil
+}
+
+func (l *Location) validate() error {
+	switch {
+	case l.Name == "":
+		return errors.New("missing name")
+	case l.Latitude < -90 || l.Latitude > 90:
+		return fmt.Errorf("latitude %v out of range", l.Latitude)
+	case l.Longitude < -180 || l.Longitude > 180:
+		return fmt.Errorf("longitude %v out of range", l.Longitude)
+	}
+	return nil
+}
+
+// SetLocations switches the checks to new locations. It waits for any run in
+// progress to finish, so the change applies from the next run.
+func (a *Agent) SetLocations(locs Locations) {
+	a.cfgMu.Lock()
+	defer a.cfgMu.Unlock()
+
+	if l := locs.Wind; l != nil {
+		c := *a.cfg.WindWeather
+		c.Latitude, c.Longitude = l.Latitude, l.Longitude
+		a.cfg.WindWeather = &c
+		a.cfg.WindLocation = l.Name
+	}
+	if l := locs.Rain; l != nil {
+		c := *a.cfg.RainWeather
+		c.Latitude, c.Longitude = l.Latitude, l.Longitude
+		a.cfg.RainWeather = &c
+		a.cfg.RainLocation = l.Name
+		if a.cfg.AirQuality != nil {
+			aq := *a.cfg.AirQuality
+			aq.Latitude, aq.Longitude = l.Latitude, l.Longitude
+			a.cfg.AirQuality = &aq
+		}
+	}
+}
+
+// WatchLocations watches the locations file and applies it when it
+// changes. An invalid file is logged and ignored, keeping the previous
+// locations. The file's directory is watched rather than the file itself,
+// since editors and config management often replace a file instead of
+// writing to it. It returns when ctx is done.
+func (a *Agent) WatchLocations(ctx context.Context, path string) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("watch locations: %w", err)
+	}
+	defer w.Close()
+	if err := w.Add(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("watch locations: %w", err)
+	}
+
+	// A save can arrive as several events; reload once they settle.
+	reload := time.NewTimer(0)
+	<-reload.C
+	defer reload.Stop()
+	name := filepath.Clean(path)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err, ok := <-w.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Printf("warning: watching locations file: %v\n", err)
+		case ev, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(ev.Name) == name && ev.Has(fsnotify.Write|fsnotify.Create) {
+				reload.Reset(locationsSettle)
+			}
+		case <-reload.C:
+			locs, err := LoadLocations(path)
+			if err != nil {
+				fmt.Printf("warning: keeping previous locations: %v\n", err)
+				continue
+			}
+			a.SetLocations(locs)
+			fmt.Printf("📍 Reloaded locations from %s\n", path)
+		}
+	}
+}
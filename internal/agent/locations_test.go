@@ -0,0 +1,103 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadLocations(t *testing.T) {
+	tests := []struct {
+		name, data string
+		wantErr    bool
+	}{
+		{"yaml", "wind:\n  name: Gatwick\n  lat: 51.15\n  lon: -0.18\n", false},
+		{"json", `{"rain": {"name": "Kew", "lat": 51.48, "lon": -0.29}}`, false},
+		{"empty", "", false},
+		{"unknown field", "wind:\n  name: Gatwick\n  latitude: 51.15\n", true},
+		{"out of range", "wind:\n  name: Gatwick\n  lat: 151.15\n  lon: -0.18\n", true},
+		{"missing name", "rain:\n  lat: 51.48\n  lon: -0.29\n", true},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "locations.yaml")
+		if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := LoadLocations(path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: LoadLocations error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestWatchLocations(t *testing.T) {
+	a := newTestAgent(t, Config{})
+	path := filepath.Join(t.TempDir(), "locations.yaml")
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	windLocation := func() string {
+		a.cfgMu.RLock()
+		defer a.cfgMu.RUnlock()
+		return a.cfg.WindLocation
+	}
+	write("wind:\n  name: Heathrow\n  lat: 51.47\n  lon: -0.45\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- a.WatchLocations(ctx, path) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	// Give the watcher time to start before changing the file.
+	time.Sleep(50 * time.Millisecond)
+
+	write("wind:\n  name: Gatwick\n  lat: 51.15\n  lon: -0.18\n")
+	deadline := time.Now().Add(2 * time.Second)
+	for windLocation() != "Gatwick" {
+		if time.Now().After(deadline) {
+			t.Fatalf("wind location %q after the edit, want Gatwick", windLocation())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// An invalid edit keeps the previous locations.
+	write("wind:\n  name: Nowhere\n  lat: 200\n  lon: 0\n")
+	time.Sleep(3 * locationsSettle)
+	if got := windLocation(); got != "Gatwick" {
+		t.Errorf("wind location %q after an invalid edit, want Gatwick", got)
+	}
+	a.cfgMu.RLock()
+	lat := a.cfg.WindWeather.Latitude
+	a.cfgMu.RUnlock()
+	if lat != 51.15 {
+		t.Errorf("wind latitude %v, want 51.15", lat)
+	}
+}
+
+// TestSetLocationsDuringRun is meant for -race: runs read the locations
+// while SetLocations replaces them.
+func TestSetLocationsDuringRun(t *testing.T) {
+	a := newTestAgent(t, Config{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 20 {
+			a.SetLocations(Locations{
+				Wind: &Location{Name: fmt.Sprint("Wind ", i), Latitude: 51, Longitude: 0},
+				Rain: &Location{Name: fmt.Sprint("Rain ", i), Latitude: 51, Longitude: 0},
+			})
+		}
+	}()
+	ctx := context.Background()
+	_ = a.RunOnce(ctx)
+	_, _ = a.Forecast(ctx, 2)
+	<-done
+}
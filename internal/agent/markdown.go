@@ -0,0 +1,158 @@
+package agent
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// defaultMarkdownFilename sorts by date and follows Jekyll's post naming.
+const defaultMarkdownFilename = "{{.Date}}-{{.Slug}}-{{.Kind}}.md"
+
+// MarkdownSink writes each report as a Markdown document with front matter,
+// ready to drop into a static site's content folder.
+type MarkdownSink struct {
+	Dir string
+	// FilenameTemplate is a text/template for the file name, with .Date
+	// (2006-01-02), .Time (150405), .Location, .Slug and .Kind. Defaults to
+	// "{{.Date}}-{{.Slug}}-{{.Kind}}.md". Runs on the same date and location
+	// overwrite each other unless .Time is used.
+	FilenameTemplate string
+}
+
+// Write renders r to a new file in Dir.
+func (s *MarkdownSink) Write(r Report) error {
+	name, err := s.filename(r)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return fmt.Errorf("create markdown dir: %w", err)
+	}
+	var b bytes.Buffer
+	if err := (MarkdownRenderer{}).Render(&b, r); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(s.Dir, name), b.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write markdown report: %w", err)
+	}
+	return nil
+}
+
+func (s *MarkdownSink) filename(r Report) (string, error) {
+	text := s.FilenameTemplate
+	if text == "" {
+		text = defaultMarkdownFilename
+	}
+	tmpl, err := template.New("filename").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parse markdown filename template: %w", err)
+	}
+	var b bytes.Buffer
+	err = tmpl.Execute(&b, map[string]string{
+		"Date":     r.IssuedAt.UTC().Format("2006-01-02"),
+		"Time":     r.IssuedAt.UTC().Format("150405"),
+		"Location": r.Location,
+		"Slug":     slugify(r.Location),
+		"Kind":     r.Kind,
+	})
+	if err != nil {
+		return "", fmt.Errorf("render markdown filename: %w", err)
+	}
+	// Keep every file inside Dir whatever the template produces.
+	name := filepath.Base(b.String())
+	if name == "." || name == string(filepath.Separator) {
+		return "", fmt.Errorf("markdown filename template produced %q", b.String())
+	}
+	return name, nil
+}
+
+var slugUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+
+func slugify(s string) string {
+	return strings.Trim(slugUnsafe.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+// MarkdownRenderer lays a report out as a Markdown document: front matter,
+// headline, table and summary.
+type MarkdownRenderer struct{}
+
+// Render implements Renderer.
+func (MarkdownRenderer) Render(w io.Writer, r Report) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "---\ntitle: %q\ndate: %s\nlocation: %q\nkind: %s\nseverity: %s\n---\n\n",
+		r.Location+" "+r.Kind+" forecast", r.IssuedAt.UTC().Format("2006-01-02T15:04:05Z"), r.Location, r.Kind, r.Severity)
+	if h := strings.TrimSpace(r.Headline); h != "" {
+		// Two trailing spaces keep the headline's line breaks in Markdown.
+		b.WriteString(strings.ReplaceAll(h, "\n", "  \n") + "\n\n")
+	}
+	if r.Table != "" {
+		b.WriteString(markdownTable(r.Table) + "\n")
+	}
+	if r.Summary != "" {
+		b.WriteString(strings.TrimSpace(r.Summary) + "\n\n")
+	}
+	if r.Footer != "" {
+		b.WriteString("_" + r.Footer + "_\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownTable converts the "a | b | c" text tables used for Telegram into
+// Markdown table syntax. Legends and notes follow the table as plain text.
+func markdownTable(text string) string {
+	rows, rest := tableCells(text)
+	var table []string
+	for i, cells := range rows {
+		table = append(table, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 {
+			table = append(table, strings.Repeat("|---", len(cells))+"|")
+		}
+	}
+	out := strings.Join(table, "\n")
+	if len(rest) > 0 {
+		if out != "" {
+			out += "\n\n"
+		}
+		out += strings.Join(rest, "  \n")
+	}
+	return out + "\n"
+}
+
+// tableCells splits an "a | b | c" text table into trimmed cells per row.
+// Rule lines are dropped and lines without columns (legends, notes) are
+// returned separately as rest.
+func tableCells(text string) (rows [][]string, rest []string) {
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		switch {
+		case strings.Trim(line, "-+ ") == "":
+			continue
+		case !strings.Contains(line, "|"):
+			rest = append(rest, line)
+		default:
+			cells := strings.Split(line, "|")
+			for i, c := range cells {
+				cells[i] = strings.TrimSpace(c)
+			}
+			rows = append(rows, cells)
+		}
+	}
+	return rows, rest
+}
+
+// writeMarkdown writes a report to the Markdown sink, if configured. Failures
+// are logged but never fail the run.
+func (a *Agent) writeMarkdown(r Report) {
+	if a.cfg.MarkdownSink == nil {
+		return
+	}
+	if err := a.cfg.MarkdownSink.Write(r); err != nil {
+		fmt.Printf("warning: %v\n", err)
+	}
+}
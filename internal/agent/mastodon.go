@@ -0,0 +1,50 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// mastodonLimit is the default maximum status length on Mastodon instances.
+const mastodonLimit = 500
+
+// MastodonNotifier posts reports as statuses to a Mastodon account.
+type MastodonNotifier struct {
+	InstanceURL string // e.g. https://mastodon.social
+	AccessToken string
+	// Visibility is "public", "unlisted", "private" or "direct". Empty uses
+	// the account's default.
+	Visibility string
+	HTTPClient *http.Client
+}
+
+// Name implements Notifier.
+func (m *MastodonNotifier) Name() string { return "mastodon" }
+
+// Notify implements Notifier. Only the headline and summary are posted, cut
+// to fit the status limit; the table is too wide for a toot.
+func (m *MastodonNotifier) Notify(ctx context.Context, r Report) error {
+	payload := map[string]string{"status": mastodonStatus(r)}
+	if m.Visibility != "" {
+		payload["visibility"] = m.Visibility
+	}
+	header := http.Header{"Authorization": {"Bearer " + m.AccessToken}}
+	url := strings.TrimRight(m.InstanceURL, "/") + "/api/v1/statuses"
+	if err := postJSON(ctx, m.HTTPClient, url, header, payload); err != nil {
+		return fmt.Errorf("mastodon: %w", err)
+	}
+	return nil
+}
+
+// mastodonStatus renders the compact report text within mastodonLimit.
+func mastodonStatus(r Report) string {
+	text := joinNonEmpty("\n\n", strings.TrimSpace(r.Headline), strings.TrimSpace(r.Summary))
+	if utf8.RuneCountInString(text) <= mastodonLimit {
+		return text
+	}
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:mastodonLimit-1])) + "…"
+}
@@ -0,0 +1,181 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/emanuelefumagalli/test-agent/internal/httpx"
+	"github.com/emanuelefumagalli/test-agent/internal/tracing"
+)
+
+// Report is the structured content of one notification. Each Notifier
+// renders it in the layout that suits its channel.
+type Report struct {
+	Kind     string    `json:"kind"` // "wind", "rain", "alert", "accuracy" or "test"
+	Location string    `json:"location"`
+	Headline string    `json:"headline"`
+	Table    string    `json:"table,omitempty"` // monospace table
+	Summary  string    `json:"summary,omitempty"`
+	Footer   string    `json:"footer,omitempty"`
+	Severity Severity  `json:"severity"`
+	IssuedAt time.Time `json:"issued_at"`
+}
+
+// PlainText renders the report as plain monospace-friendly text.
+func (r Report) PlainText() string {
+	return joinNonEmpty("\n\n", r.Headline, strings.TrimRight(r.Table, "\n"), r.Summary, r.Footer)
+}
+
+// joinNonEmpty joins the non-blank parts with sep.
+func joinNonEmpty(sep string, parts ...string) string {
+	var kept []string
+	for _, p := range parts {
+		if strings.TrimSpace(p) != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, sep)
+}
+
+// Notifier delivers a report to one channel.
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, r Report) error
+}
+
+// notify sends r to the notifiers routed for its severity (falling back to
+// the defaults) and joins their failures, each prefixed by the notifier name.
+func (a *Agent) notify(ctx context.Context, r Report) (delivered int, err error) {
+	targets := a.cfg.Routes[r.Severity]
+	if len(targets) == 0 {
+		targets = a.notifiers
+	}
+	var errs []error
+	for _, n := range targets {
+		_, span := tracing.Start(ctx, "notify", attribute.String("notifier", n.Name()), attribute.String("kind", r.Kind), attribute.String("location", r.Location))
+		nerr := n.Notify(ctx, r)
+		tracing.End(span, nerr)
+		record(ctx, func(res *RunResult) {
+			res.Notifications = append(res.Notifications, NotifyResult{Notifier: n.Name(), Kind: r.Kind, Location: r.Location, Err: nerr})
+		})
+		if nerr != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", n.Name(), nerr))
+			continue
+		}
+		delivered++
+	}
+	return delivered, errors.Join(errs...)
+}
+
+// testMessage is the canned headline TestNotifications sends.
+const testMessage = "✅ personal-weather-agent test message"
+
+// TestNotifications sends a canned test message to every configured
+// notifier, including those only reached through Routes, printing whether
+// each got it. Unlike a forecast run it fetches nothing, so new credentials
+// can be checked straight away. The error joins each failure, prefixed by
+// the channel.
+func (a *Agent) TestNotifications(ctx context.Context) error {
+	type channel struct {
+		label string
+		n     Notifier
+	}
+	var channels []channel
+	seen := make(map[Notifier]bool)
+	add := func(n Notifier, label string) {
+		if !seen[n] {
+			seen[n] = true
+			channels = append(channels, channel{label, n})
+		}
+	}
+	for _, n := range a.notifiers {
+		add(n, n.Name())
+	}
+	severities := slices.Sorted(maps.Keys(a.cfg.Routes))
+	for _, sev := range severities {
+		for _, n := range a.cfg.Routes[sev] {
+			add(n, fmt.Sprintf("%s (%s route)", n.Name(), sev))
+		}
+	}
+	if len(channels) == 0 {
+		return errors.New("no notifiers configured")
+	}
+
+	r := Report{
+		Kind:     "test",
+		Location: a.cfg.WindLocation,
+		Headline: testMessage,
+		IssuedAt: a.now(),
+	}
+	var errs []error
+	for _, c := range channels {
+		if err := c.n.Notify(ctx, r); err != nil {
+			fmt.Printf("❌ %s: %v\n", c.label, err)
+			errs = append(errs, fmt.Errorf("%s: %w", c.label, err))
+			continue
+		}
+		fmt.Printf("✅ %s: delivered\n", c.label)
+	}
+	return errors.Join(errs...)
+}
+
+// WebhookNotifier POSTs the report as JSON to an arbitrary URL.
+type WebhookNotifier struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+// Name implements Notifier.
+func (w *WebhookNotifier) Name() string { return "webhook" }
+
+// Notify implements Notifier.
+func (w *WebhookNotifier) Notify(ctx context.Context, r Report) error {
+	return postJSON(ctx, w.HTTPClient, w.URL, nil, r)
+}
+
+// postJSON sends payload as JSON with any extra headers and treats a non-2xx
+// status as an error.
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if client == nil {
+		client = httpx.NewClient(10*time.Second, "")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			fmt.Printf("warning: close response body: %v\n", cerr)
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
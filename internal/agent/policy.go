@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/emanuelefumagalli/test-agent/internal/ollama"
+	"github.com/emanuelefumagalli/test-agent/internal/tracing"
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// RunPolicy decides how a run degrades when parts of it fail.
+type RunPolicy struct {
+	// FetchRetries is how many extra attempts a failed forecast fetch gets.
+	FetchRetries int
+	// OllamaRetries is how many extra attempts a failed summary gets.
+	OllamaRetries int
+	// RetryDelay is the pause between attempts, multiplied by the attempt number.
+	RetryDelay time.Duration
+	// AlwaysSendTable sends the table even when no summary could be
+	// generated. When false, a run without a summary sends nothing.
+	AlwaysSendTable bool
+	// FailIfNoNotifierSucceeds adds errNoDelivery to the run's error when
+	// notifiers are configured but none delivered the report.
+	FailIfNoNotifierSucceeds bool
+}
+
+// DefaultPolicy fetches once and falls back to the bare table when Ollama
+// fails.
+func DefaultPolicy() RunPolicy {
+	return RunPolicy{
+		RetryDelay:      5 * time.Second,
+		AlwaysSendTable: true,
+	}
+}
+
+// errNoDelivery reports that every configured notifier failed.
+var errNoDelivery = errors.New("no notifier delivered the report")
+
+// retry calls fn up to 1+retries times, stopping early on success, context
+// cancellation, or a weather error that retrying cannot fix. Within a traced
+// run the attempts are recorded as one span named what.
+func retry[T any](ctx context.Context, retries int, delay time.Duration, what string, fn func() (T, error)) (T, error) {
+	var (
+		v   T
+		err error
+	)
+	ctx, span := tracing.Start(ctx, what)
+	for attempt := 0; ; attempt++ {
+		v, err = fn()
+		if err == nil || attempt >= retries || !retryable(err) {
+			span.SetAttributes(attribute.Int("attempts", attempt+1))
+			tracing.End(span, err)
+			return v, err
+		}
+		fmt.Printf("%s failed (attempt %d/%d): %v\n", what, attempt+1, retries+1, err)
+		select {
+		case <-ctx.Done():
+			err = errors.Join(err, ctx.Err())
+			tracing.End(span, err)
+			return v, err
+		case <-time.After(delay * time.Duration(attempt+1)):
+		}
+	}
+}
+
+// retryable reports whether err may go away on a later attempt. A missing
+// Ollama model won't; other errors that aren't a weather.WeatherError are
+// assumed transient.
+func retryable(err error) bool {
+	if errors.Is(err, ollama.ErrModelNotFound) {
+		return false
+	}
+	var werr *weather.WeatherError
+	if errors.As(err, &werr) {
+		return werr.Temporary()
+	}
+	return true
+}
+
+// deliver sends a report to all notifiers according to the policy. Reports
+// are collected for a manual run's response even when quiet hours hold them.
+func (a *Agent) deliver(ctx context.Context, r Report) error {
+	p := a.policy
+	collect(ctx, r)
+	if a.quiet(a.now()) {
+		return nil
+	}
+	if r.Summary == "" && !p.AlwaysSendTable {
+		fmt.Println("no summary available, skipping send per policy")
+		return nil
+	}
+	delivered, err := a.notify(ctx, r)
+	if delivered == 0 && len(a.notifiers) > 0 && p.FailIfNoNotifierSucceeds {
+		return errors.Join(err, errNoDelivery)
+	}
+	return err
+}
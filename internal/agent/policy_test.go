@@ -0,0 +1,53 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDeliverDefaultPolicy(t *testing.T) {
+	n := &recordingNotifier{name: "test"}
+	a := New(Config{Notifiers: []Notifier{n}})
+	if a.policy != DefaultPolicy() {
+		t.Errorf("policy = %+v, want DefaultPolicy", a.policy)
+	}
+
+	// Without a summary the default policy still sends the table.
+	if err := a.deliver(context.Background(), Report{Kind: "test", Table: "table"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(n.sent()); got != 1 {
+		t.Fatalf("sent %d reports, want 1", got)
+	}
+}
+
+func TestDeliverPolicy(t *testing.T) {
+	down := errors.New("down")
+	tests := []struct {
+		name      string
+		policy    RunPolicy
+		summary   string
+		notifyErr error
+		sent      int
+		wantErr   error
+	}{
+		{"table only held back", RunPolicy{}, "", nil, 0, nil},
+		{"summary sent", RunPolicy{}, "Calm.", nil, 1, nil},
+		{"failure passed on", RunPolicy{AlwaysSendTable: true}, "", down, 1, down},
+		{"no delivery fails", RunPolicy{AlwaysSendTable: true, FailIfNoNotifierSucceeds: true}, "", down, 1, errNoDelivery},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &recordingNotifier{name: "test", err: tt.notifyErr}
+			a := New(Config{Notifiers: []Notifier{n}, Policy: &tt.policy})
+			err := a.deliver(context.Background(), Report{Kind: "test", Table: "table", Summary: tt.summary})
+			if (tt.wantErr == nil) != (err == nil) || !errors.Is(err, tt.wantErr) {
+				t.Errorf("deliver error = %v, want %v", err, tt.wantErr)
+			}
+			if got := len(n.sent()); got != tt.sent {
+				t.Errorf("sent %d reports, want %d", got, tt.sent)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package agent
+
+import (
+	"strings"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// timingKeys maps each wet weather.RainTiming to its description.
+var timingKeys = map[weather.RainTiming]msgKey{
+	weather.TimingMorning:   msgTimingMorning,
+	weather.TimingAfternoon: msgTimingAfternoon,
+	weather.TimingEvening:   msgTimingEvening,
+	weather.TimingAllDay:    msgTimingAllDay,
+	weather.TimingScattered: msgTimingScattered,
+}
+
+// rainTimingLines says when the rain falls on each wet day, e.g. "Tue: rain
+// in the morning, clearing by midday (4.2 mm)". It returns "" when every day
+// is dry.
+func rainTimingLines(days []weather.RainForecast, tr translator) string {
+	var lines []string
+	for _, d := range days {
+		key, ok := timingKeys[d.Timing()]
+		if !ok {
+			continue
+		}
+		lines = append(lines, tr.T(msgRainTimingDay, weekdayNames[tr.lang][d.Date.Weekday()], tr.T(key), d.FormatAmount(d.PrecipMM)))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return tr.T(msgRainTiming) + "\n" + strings.Join(lines, "\n")
+}
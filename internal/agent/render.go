@@ -0,0 +1,71 @@
+package agent
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Renderer lays a Report out in one output format. Each output target picks
+// the renderer that suits it, so a new format is one more implementation.
+type Renderer interface {
+	Render(w io.Writer, r Report) error
+}
+
+// ConsoleRenderer renders the report as plain text, as printed and logged.
+type ConsoleRenderer struct{}
+
+// Render implements Renderer.
+func (ConsoleRenderer) Render(w io.Writer, r Report) error {
+	_, err := io.WriteString(w, r.PlainText()+"\n")
+	return err
+}
+
+// CSVRenderer renders the report's table as CSV, one record per row with the
+// header first. Rule lines, legends and the report's free text are left out;
+// a report without a table renders nothing.
+type CSVRenderer struct{}
+
+// Render implements Renderer.
+func (CSVRenderer) Render(w io.Writer, r Report) error {
+	rows, _ := tableCells(r.Table)
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(rows); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+	return nil
+}
+
+// JSONRenderer renders the report as an indented JSON object, in the shape
+// WebhookNotifier posts.
+type JSONRenderer struct{}
+
+// Render implements Renderer.
+func (JSONRenderer) Render(w io.Writer, r Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return fmt.Errorf("write json: %w", err)
+	}
+	return nil
+}
+
+// ParseRenderer returns the renderer named by format: "text" (or empty),
+// "telegram", "markdown", "csv" or "json".
+func ParseRenderer(format string) (Renderer, error) {
+	switch format {
+	case "", "text":
+		return ConsoleRenderer{}, nil
+	case "telegram":
+		return TelegramRenderer{ParseMode: parseModeMarkdown}, nil
+	case "markdown":
+		return MarkdownRenderer{}, nil
+	case "csv":
+		return CSVRenderer{}, nil
+	case "json":
+		return JSONRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q (want text, telegram, markdown, csv or json)", format)
+	}
+}
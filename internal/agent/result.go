@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"context"
+	"sync"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// RunResult is what one RunOnceResult produced: the forecasts behind the
+// reports, the reports themselves and how each delivery went. Checks that
+// failed before fetching leave their fields empty.
+type RunResult struct {
+	// Location is the wind location, and Days its forecast.
+	Location string
+	Days     []weather.ForecastDay
+	// Easterly analyses Days as the report did.
+	Easterly EasterlyAnalysis
+	// Summary is the wind report's Ollama summary, "" when it failed.
+	Summary string
+
+	RainLocation string
+	Rain         []weather.RainForecast
+	RainSummary  string
+
+	// Reports holds every report the run generated, in order, including
+	// those quiet hours or the policy held back.
+	Reports []Report
+	// Notifications records each notifier send.
+	Notifications []NotifyResult
+}
+
+// NotifyResult is one notifier's attempt to send one report.
+type NotifyResult struct {
+	Notifier string
+	Kind     string
+	Location string
+	// Err is nil when the report was delivered.
+	Err error
+}
+
+// RunOnceResult is RunOnce, also returning what the run produced.
+func (a *Agent) RunOnceResult(ctx context.Context) (RunResult, error) {
+	return a.runChecksResult(ctx, checkWind, checkRain)
+}
+
+// runChecksResult is runChecks, also returning what the run produced.
+func (a *Agent) runChecksResult(ctx context.Context, checks ...string) (RunResult, error) {
+	c := &runCollector{}
+	err := a.runChecks(context.WithValue(ctx, collectKey{}, c), checks...)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.res, err
+}
+
+// collectKey is the context key for a run's collector.
+type collectKey struct{}
+
+// runCollector gathers a RunResult as the run goes.
+type runCollector struct {
+	mu  sync.Mutex
+	res RunResult
+}
+
+// record applies fn to the run's result, if the context carries a collector.
+func record(ctx context.Context, fn func(*RunResult)) {
+	c, ok := ctx.Value(collectKey{}).(*runCollector)
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fn(&c.res)
+}
+
+// collect records r on the run's collector, if the context carries one.
+func collect(ctx context.Context, r Report) {
+	record(ctx, func(res *RunResult) { res.Reports = append(res.Reports, r) })
+}
@@ -0,0 +1,45 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRunResultNotifications(t *testing.T) {
+	errDown := errors.New("down")
+	ok := &recordingNotifier{name: "ok"}
+	down := &recordingNotifier{name: "down", err: errDown}
+	a := newTestAgent(t, Config{Notifiers: []Notifier{ok, down}})
+
+	res, err := a.RunOnceResult(context.Background())
+	if !errors.Is(err, errDown) {
+		t.Fatalf("RunOnceResult error = %v, want the notifier's", err)
+	}
+	sent := map[string]int{}
+	for _, n := range res.Notifications {
+		switch {
+		case n.Notifier == "ok" && n.Err != nil:
+			t.Errorf("%s report to ok failed: %v", n.Kind, n.Err)
+		case n.Notifier == "down" && !errors.Is(n.Err, errDown):
+			t.Errorf("%s report to down: error %v, want %v", n.Kind, n.Err, errDown)
+		}
+		sent[n.Notifier+" "+n.Kind]++
+	}
+	for _, key := range []string{"ok wind", "down wind", "ok rain", "down rain"} {
+		if sent[key] == 0 {
+			t.Errorf("no %s notification in %v", key, res.Notifications)
+		}
+	}
+
+	// A scheduled trigger runs and records its one check alone.
+	res, _ = a.runChecksResult(context.Background(), checkRain)
+	if len(res.Notifications) == 0 {
+		t.Fatal("rain run recorded no notifications")
+	}
+	for _, n := range res.Notifications {
+		if n.Kind != checkRain {
+			t.Errorf("rain run recorded a %s notification", n.Kind)
+		}
+	}
+}
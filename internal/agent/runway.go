@@ -0,0 +1,44 @@
+package agent
+
+import "github.com/emanuelefumagalli/test-agent/internal/weather"
+
+// Runways names the runways landing aircraft use in each operating
+// direction, e.g. "09L/09R" for easterly ops.
+type Runways struct {
+	Easterly string
+	Westerly string
+}
+
+// HeathrowRunways are Heathrow's landing runways, the Runways default.
+var HeathrowRunways = Runways{Easterly: "09L/09R", Westerly: "27L/27R"}
+
+// withDefaults fills empty labels from HeathrowRunways.
+func (r Runways) withDefaults() Runways {
+	if r.Easterly == "" {
+		r.Easterly = HeathrowRunways.Easterly
+	}
+	if r.Westerly == "" {
+		r.Westerly = HeathrowRunways.Westerly
+	}
+	return r
+}
+
+// runwayLine translates the first day's direction into the likely runway
+// configuration, e.g. "Today: landing on 09L/09R — easterly ops". Light
+// easterlies under MinEasterlySpeed could go either way and say so. It
+// returns "" when RunwayReport is off or the direction is unknown.
+func (a *Agent) runwayLine(days []weather.ForecastDay) string {
+	if !a.cfg.RunwayReport || len(days) == 0 || days[0].DirUnknown {
+		return ""
+	}
+	d, rw := days[0], a.cfg.Runways
+	label := a.dayLabel(d.Date)
+	switch {
+	case dayEasterly(d, a.cfg.MinEasterlySpeed):
+		return a.tr.T(msgRunwayEasterly, label, rw.Easterly)
+	case lightEasterly(d, a.cfg.MinEasterlySpeed):
+		return a.tr.T(msgRunwayEither, label, rw.Easterly, rw.Westerly)
+	default:
+		return a.tr.T(msgRunwayWesterly, label, rw.Westerly)
+	}
+}
@@ -0,0 +1,172 @@
+package agent
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Scheduler decides when a check runs.
+type Scheduler interface {
+	// Next returns the first run time strictly after now.
+	Next(now time.Time) time.Time
+}
+
+// DailyScheduler runs once a day at Hour:Minute in Location (UTC when nil).
+type DailyScheduler struct {
+	Hour, Minute int
+	Location     *time.Location
+}
+
+// Next implements Scheduler.
+func (s DailyScheduler) Next(now time.Time) time.Time {
+	loc := s.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	now = now.In(loc)
+	next := time.Date(now.Year(), now.Month(), now.Day(), s.Hour, s.Minute, 0, 0, loc)
+	if !now.Before(next) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// IntervalScheduler runs every Every, aligned to multiples of it since the
+// zero time, so "6h" fires at 00:00, 06:00, 12:00 and 18:00 UTC.
+type IntervalScheduler struct {
+	Every time.Duration
+}
+
+// Next implements Scheduler.
+func (s IntervalScheduler) Next(now time.Time) time.Time {
+	return now.Truncate(s.Every).Add(s.Every)
+}
+
+// ParseSchedule reads "@every <duration>" as an IntervalScheduler and
+// anything else as a cron spec in loc.
+func ParseSchedule(spec string, loc *time.Location) (Scheduler, error) {
+	if every, ok := strings.CutPrefix(strings.TrimSpace(spec), "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(every))
+		if err != nil || d < time.Minute {
+			return nil, fmt.Errorf("schedule %q: want a duration of at least 1m", spec)
+		}
+		return IntervalScheduler{Every: d}, nil
+	}
+	return ParseCron(spec, loc)
+}
+
+// nextRun returns the first run time from s strictly after now, skipping any
+// day the agent is not configured to run on. A skipped day is passed over
+// whole, so a schedule firing every minute doesn't step through each slot of
+// a weekend.
+func (a *Agent) nextRun(s Scheduler, now time.Time) time.Time {
+	next := s.Next(now)
+	// Give up after a week of skipped days, when the schedule only fires on
+	// days that are skipped, and run anyway rather than never.
+	for i := 0; i < 7 && !a.runsOn(next.Weekday()); i++ {
+		if next.Weekday() == time.Saturday || next.Weekday() == time.Sunday {
+			fmt.Printf("skipping weekend run on %s\n", next.Format("Mon 02 Jan"))
+		} else {
+			fmt.Printf("skipping run on %s\n", next.Format("Mon 02 Jan"))
+		}
+		// Next is strictly after, so ask from just before the next midnight.
+		midnight := time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+		next = s.Next(midnight.Add(-time.Nanosecond))
+	}
+	return next
+}
+
+// nextSlot returns the schedule's next slot after both now and prev, the
+// slot last run, and when to run it: shifted by a random offset within
+// ±ScheduleJitter, drawn afresh each time, but never before now. Counting
+// from prev stops a run jittered early from running its slot twice.
+func (a *Agent) nextSlot(s Scheduler, prev time.Time) (slot, at time.Time) {
+	now := a.now()
+	from := now
+	if prev.After(now) {
+		from = prev
+	}
+	slot = a.nextRun(s, from)
+	j := a.cfg.ScheduleJitter
+	if j <= 0 {
+		return slot, slot
+	}
+	a.randMu.Lock()
+	offset := time.Duration(a.rand.Int64N(2*int64(j)+1)) - j
+	a.randMu.Unlock()
+	at = slot.Add(offset)
+	if at.Before(now) {
+		at = now
+	}
+	return slot, at
+}
+
+// runsOn reports whether scheduled runs are enabled on day.
+func (a *Agent) runsOn(day time.Weekday) bool {
+	if a.cfg.OnlyOnWeekdays && (day == time.Saturday || day == time.Sunday) {
+		return false
+	}
+	return len(a.cfg.RunDays) == 0 || slices.Contains(a.cfg.RunDays, day)
+}
+
+// Names of the scheduled checks, used as keys in the persisted state.
+const (
+	checkWind = "wind"
+	checkRain = "rain"
+)
+
+// markRan records that a check ran at t.
+func (a *Agent) markRan(check string, t time.Time) {
+	err := a.updateState(func(st *state) {
+		if st.LastRuns == nil {
+			st.LastRuns = make(map[string]time.Time)
+		}
+		st.LastRuns[check] = t
+	})
+	if err != nil {
+		fmt.Printf("warning: save state: %v\n", err)
+	}
+}
+
+// missedRun reports whether a slot of s has passed today (London time)
+// without the check having run since, e.g. after a reboot or sleep. Slots
+// missed on earlier days are not caught up.
+func (a *Agent) missedRun(check string, s Scheduler, now time.Time) bool {
+	local := now.In(a.london)
+	// Next is strictly after, so start just before midnight to include a
+	// slot at 00:00.
+	from := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, a.london).Add(-time.Nanosecond)
+	a.stateMu.Lock()
+	last, ok := a.state.LastRuns[check]
+	a.stateMu.Unlock()
+	// A run jittered early still covers the slot it ran for.
+	if covered := last.Add(a.cfg.ScheduleJitter); ok && covered.After(from) {
+		from = covered
+	}
+	return !a.nextRun(s, from).After(now)
+}
+
+// inQuietHours reports whether now falls in the configured quiet hours.
+func (a *Agent) inQuietHours(now time.Time) bool {
+	start, end := a.cfg.QuietHours[0], a.cfg.QuietHours[1]
+	if start == end {
+		return false
+	}
+	h := now.In(a.london).Hour()
+	if start < end {
+		return h >= start && h < end
+	}
+	// Wraps midnight, e.g. 22 -> 7
+	return h >= start || h < end
+}
+
+// quiet is inQuietHours with the log line callers want when suppressing.
+func (a *Agent) quiet(now time.Time) bool {
+	if !a.inQuietHours(now) {
+		return false
+	}
+	fmt.Println("within quiet hours, suppressing notification")
+	return true
+}
@@ -0,0 +1,192 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestNextRunSkipsWeekend(t *testing.T) {
+	friday := time.Date(2025, 1, 17, 11, 0, 0, 0, time.UTC)
+	a := New(Config{OnlyOnWeekdays: true})
+
+	got := a.nextRun(DailyScheduler{Hour: 10}, friday)
+	if want := time.Date(2025, 1, 20, 10, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("next run after Friday's = %s, want Monday %s", got, want)
+	}
+
+	// Before Friday's slot, Friday itself still runs.
+	morning := time.Date(2025, 1, 17, 9, 0, 0, 0, time.UTC)
+	if got, want := a.nextRun(DailyScheduler{Hour: 10}, morning), time.Date(2025, 1, 17, 10, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("next run on Friday morning = %s, want %s", got, want)
+	}
+}
+
+func TestNextRunIntervalOverWeekend(t *testing.T) {
+	a := New(Config{OnlyOnWeekdays: true})
+	every := IntervalScheduler{Every: time.Minute}
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"Friday evening", time.Date(2025, 1, 17, 23, 50, 0, 0, time.UTC), time.Date(2025, 1, 17, 23, 51, 0, 0, time.UTC)},
+		{"last slot on Friday", time.Date(2025, 1, 17, 23, 59, 0, 0, time.UTC), time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)},
+		{"Saturday noon", time.Date(2025, 1, 18, 12, 7, 0, 0, time.UTC), time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := a.nextRun(every, tt.now); !got.Equal(tt.want) {
+			t.Errorf("%s: next run = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNextRunRunDays(t *testing.T) {
+	friday := time.Date(2025, 1, 17, 11, 0, 0, 0, time.UTC)
+	a := New(Config{RunDays: []time.Weekday{time.Wednesday}})
+
+	got := a.nextRun(DailyScheduler{Hour: 10}, friday)
+	if want := time.Date(2025, 1, 22, 10, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("next run = %s, want Wednesday %s", got, want)
+	}
+}
+
+func TestMissedRun(t *testing.T) {
+	slot := DailyScheduler{Hour: 7, Minute: 30}
+	at := func(day, hour, minute int) time.Time { return time.Date(2025, 1, day, hour, minute, 0, 0, time.UTC) }
+	tests := []struct {
+		name string
+		last time.Time // zero for no recorded run
+		now  time.Time
+		want bool
+	}{
+		{"first start before today's slot", time.Time{}, at(6, 6, 0), false},
+		{"first start after today's slot", time.Time{}, at(6, 10, 0), true},
+		{"ran today", at(6, 7, 30), at(6, 10, 0), false},
+		{"ran yesterday, before today's slot", at(5, 7, 30), at(6, 6, 0), false},
+		{"ran yesterday, after today's slot", at(5, 7, 30), at(6, 10, 0), true},
+		{"down for days, before today's slot", at(1, 7, 30), at(6, 6, 0), false},
+	}
+	for _, tt := range tests {
+		a := New(Config{Now: func() time.Time { return tt.now }})
+		if !tt.last.IsZero() {
+			a.markRan(checkRain, tt.last)
+		}
+		if got := a.missedRun(checkRain, slot, tt.now); got != tt.want {
+			t.Errorf("%s: missedRun = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// fakeScheduler yields its times in turn, then nothing for a year.
+type fakeScheduler struct {
+	times []time.Time
+}
+
+func (s *fakeScheduler) Next(now time.Time) time.Time {
+	if len(s.times) == 0 {
+		return now.AddDate(1, 0, 0)
+	}
+	next := s.times[0]
+	s.times = s.times[1:]
+	return next
+}
+
+func TestRunFollowsScheduler(t *testing.T) {
+	n := &recordingNotifier{name: "test"}
+	a := newTestAgent(t, Config{
+		Notifiers:    []Notifier{n},
+		WindSchedule: &fakeScheduler{},
+		RainSchedule: &fakeScheduler{times: []time.Time{testNow, testNow, testNow}},
+	})
+	count := func(kind string) int {
+		c := 0
+		for _, r := range n.sent() {
+			if r.Kind == kind {
+				c++
+			}
+		}
+		return c
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- a.Run(ctx) }()
+	deadline := time.Now().Add(5 * time.Second)
+	for count(checkRain) < 3 || count(checkWind) < 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d rain and %d wind reports, want 3 and 1", count(checkRain), count(checkWind))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// The schedulers have no more triggers, so nothing else runs.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run returned %v, want context.Canceled", err)
+	}
+	if got := count(checkRain); got != 3 {
+		t.Errorf("%d rain reports, want one per trigger (3)", got)
+	}
+	// The wind check runs once at startup.
+	if got := count(checkWind); got != 1 {
+		t.Errorf("%d wind reports, want 1", got)
+	}
+}
+
+func TestNextSlotJitter(t *testing.T) {
+	slot := DailyScheduler{Hour: 7, Minute: 30}
+	at := func(day, hour, minute int) time.Time { return time.Date(2025, 1, day, hour, minute, 0, 0, time.UTC) }
+	jitter := 10 * time.Minute
+	newAgent := func(now *time.Time) *Agent {
+		return New(Config{
+			ScheduleJitter: jitter,
+			Now:            func() time.Time { return *now },
+			Rand:           rand.New(rand.NewPCG(1, 2)),
+		})
+	}
+
+	// Well before the slot, runs land anywhere within ±jitter of it.
+	now := at(6, 6, 0)
+	a := newAgent(&now)
+	var early, late bool
+	for range 200 {
+		s, got := a.nextSlot(slot, time.Time{})
+		if want := at(6, 7, 30); !s.Equal(want) {
+			t.Fatalf("slot %s, want %s", s, want)
+		}
+		if got.Before(s.Add(-jitter)) || got.After(s.Add(jitter)) {
+			t.Fatalf("run at %s, want within %s of %s", got, jitter, s)
+		}
+		early = early || got.Before(s)
+		late = late || got.After(s)
+	}
+	if !early || !late {
+		t.Errorf("runs early %v, late %v; want both over 200 draws", early, late)
+	}
+
+	// The same seed draws the same offsets.
+	_, first := newAgent(&now).nextSlot(slot, time.Time{})
+	_, again := newAgent(&now).nextSlot(slot, time.Time{})
+	if !first.Equal(again) {
+		t.Errorf("seeded runs at %s and %s, want the same", first, again)
+	}
+
+	// Inside the jitter window, a run is never scheduled in the past.
+	now = at(6, 7, 25)
+	a = newAgent(&now)
+	for range 200 {
+		if _, got := a.nextSlot(slot, time.Time{}); got.Before(now) {
+			t.Fatalf("run at %s, before now %s", got, now)
+		}
+	}
+
+	// A run jittered early, at 07:22, doesn't repeat its 07:30 slot.
+	now = at(6, 7, 22)
+	a = newAgent(&now)
+	if s, _ := a.nextSlot(slot, at(6, 7, 30)); !s.Equal(at(7, 7, 30)) {
+		t.Errorf("slot after an early run = %s, want tomorrow's %s", s, at(7, 7, 30))
+	}
+}
@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Server exposes the agent over HTTP. POST /run triggers an immediate
+// fetch-and-notify and responds with the reports it produced as JSON.
+// POST /telegram receives bot commands when Webhook is set.
+type Server struct {
+	Agent *Agent
+	// Token must be sent as "Authorization: Bearer <token>". An empty token
+	// rejects every request.
+	Token string
+	// Webhook, when set, handles Telegram updates at POST /telegram.
+	Webhook *TelegramWebhook
+
+	// running is held for the duration of a manual run.
+	running sync.Mutex
+}
+
+// runResponse is the JSON body returned by POST /run.
+type runResponse struct {
+	Reports []Report `json:"reports"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// Handler returns the server's routes.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /run", s.handleRun)
+	if s.Webhook != nil {
+		mux.Handle("POST /telegram", s.Webhook)
+	}
+	return mux
+}
+
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !s.running.TryLock() {
+		http.Error(w, "a run is already in progress", http.StatusTooManyRequests)
+		return
+	}
+	defer s.running.Unlock()
+
+	fmt.Println("🔔 Manual run requested over HTTP")
+	res, err := s.Agent.RunOnceResult(r.Context())
+
+	resp := runResponse{Reports: res.Reports}
+	if resp.Reports == nil {
+		resp.Reports = []Report{}
+	}
+	for _, e := range flattenErrors(err) {
+		resp.Errors = append(resp.Errors, e.Error())
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		fmt.Printf("warning: write /run response: %v\n", err)
+	}
+}
+
+// authorized checks the bearer token in constant time.
+func (s *Server) authorized(r *http.Request) bool {
+	if s.Token == "" {
+		return false
+	}
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(s.Token)) == 1
+}
+
+// flattenErrors returns the leaves of an errors.Join tree.
+func flattenErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var out []error
+		for _, e := range joined.Unwrap() {
+			out = append(out, flattenErrors(e)...)
+		}
+		return out
+	}
+	return []error{err}
+}
@@ -0,0 +1,56 @@
+package agent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServerRun(t *testing.T) {
+	n := &recordingNotifier{name: "test"}
+	s := &Server{Agent: newTestAgent(t, Config{Notifiers: []Notifier{n}}), Token: "secret"}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+
+	run := func(auth string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/run", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	for _, auth := range []string{"", "Bearer wrong", "secret"} {
+		if resp := run(auth); resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: status %d, want 401", auth, resp.StatusCode)
+		}
+	}
+	if len(n.sent()) != 0 {
+		t.Fatal("unauthorized requests ran the checks")
+	}
+
+	resp := run("Bearer secret")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d, want 200", resp.StatusCode)
+	}
+	var body struct {
+		Reports []json.RawMessage `json:"reports"`
+		Errors  []string          `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Reports) == 0 || len(body.Reports) != len(n.sent()) {
+		t.Errorf("response has %d reports, %d were sent", len(body.Reports), len(n.sent()))
+	}
+	if len(body.Errors) != 0 {
+		t.Errorf("errors %v", body.Errors)
+	}
+}
@@ -0,0 +1,52 @@
+package agent
+
+import "github.com/emanuelefumagalli/test-agent/internal/weather"
+
+// Severity grades a report so it can be routed to different destinations.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeveritySevere
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeveritySevere:
+		return "severe"
+	default:
+		return "info"
+	}
+}
+
+// daySeverity grades a single day: thunderstorms and gusts at the severe
+// threshold are severe, gusts at the alert threshold are a warning.
+func (a *Agent) daySeverity(d weather.ForecastDay) Severity {
+	switch {
+	case weather.IsThunderstorm(d.WeatherCode):
+		return SeveritySevere
+	case a.cfg.SevereGustThreshold > 0 && d.WindGustMax >= a.cfg.SevereGustThreshold:
+		return SeveritySevere
+	case a.cfg.GustAlertThreshold > 0 && d.WindGustMax >= a.cfg.GustAlertThreshold:
+		return SeverityWarning
+	default:
+		return SeverityInfo
+	}
+}
+
+// forecastSeverity is the worst severity across days.
+func (a *Agent) forecastSeverity(days []weather.ForecastDay) Severity {
+	worst := SeverityInfo
+	for _, d := range days {
+		worst = max(worst, a.daySeverity(d))
+	}
+	return worst
+}
+
+// MarshalText renders the severity by name in JSON payloads.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
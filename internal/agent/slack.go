@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// SlackNotifier posts reports to a Slack incoming webhook using Block Kit.
+type SlackNotifier struct {
+	WebhookURL string
+	HTTPClient *http.Client
+}
+
+// Name implements Notifier.
+func (s *SlackNotifier) Name() string { return "slack" }
+
+// Notify implements Notifier.
+func (s *SlackNotifier) Notify(ctx context.Context, r Report) error {
+	return postJSON(ctx, s.HTTPClient, s.WebhookURL, nil, slackPayload(r))
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+// slackPayload renders a report as mrkdwn blocks: bold headline, fenced
+// table, summary and a context footer.
+func slackPayload(r Report) map[string]any {
+	var blocks []slackBlock
+	section := func(text string) {
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: text}})
+	}
+	if r.Headline != "" {
+		section("*" + slackEscape(strings.TrimSpace(r.Headline)) + "*")
+	}
+	if r.Table != "" {
+		section("```\n" + slackEscape(r.Table) + "```")
+	}
+	if r.Summary != "" {
+		section(slackEscape(r.Summary))
+	}
+	if r.Footer != "" {
+		blocks = append(blocks, slackBlock{Type: "context", Elements: []slackText{{Type: "mrkdwn", Text: slackEscape(r.Footer)}}})
+	}
+	return map[string]any{
+		"text":   r.Headline, // notification fallback
+		"blocks": blocks,
+	}
+}
+
+// slackEscape escapes the three characters Slack treats as control sequences.
+var slackEscape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
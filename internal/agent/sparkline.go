@@ -0,0 +1,42 @@
+package agent
+
+import (
+	"strings"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a one-line Unicode block chart scaled between
+// the series min and max. A flat series renders as a row of the lowest block.
+func Sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+
+	var b strings.Builder
+	span := hi - lo
+	for _, v := range values {
+		idx := 0
+		if span > 0 {
+			idx = int((v - lo) / span * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[idx])
+	}
+	return b.String()
+}
+
+// windSparkline renders the WindSpeedMax series of a forecast.
+func windSparkline(days []weather.ForecastDay) string {
+	values := make([]float64, len(days))
+	for i, d := range days {
+		values[i] = d.WindSpeedMax
+	}
+	return Sparkline(values)
+}
@@ -0,0 +1,52 @@
+package agent
+
+import "github.com/emanuelefumagalli/test-agent/internal/weather"
+
+// defaultStableTolerance is the StableTolerance used when unset, in km/h
+// whatever the wind check's unit.
+const defaultStableTolerance = 10
+
+// stableRun returns how many days from the start of days keep the same
+// direction, easterly or westerly, with every max wind speed within tol of
+// the others. Unknown directions and light easterlies, which could go
+// either way, end the run.
+func stableRun(days []weather.ForecastDay, minSpeed, tol float64) (n int, easterly bool) {
+	lo, hi := 0.0, 0.0
+	for i, d := range days {
+		if d.DirUnknown || lightEasterly(d, minSpeed) {
+			return i, easterly
+		}
+		east := dayEasterly(d, minSpeed)
+		if i == 0 {
+			easterly, lo, hi = east, d.WindSpeedMax, d.WindSpeedMax
+			continue
+		}
+		lo, hi = min(lo, d.WindSpeedMax), max(hi, d.WindSpeedMax)
+		if east != easterly || hi-lo > tol {
+			return i, easterly
+		}
+	}
+	return len(days), easterly
+}
+
+// stableLine reports a settled spell at the start of the forecast, e.g.
+// "Stable westerly through Fri 17 Jan — no action needed", when it lasts at
+// least StableDays. It returns "" when the wind varies sooner.
+func (a *Agent) stableLine(days []weather.ForecastDay) string {
+	if a.cfg.StableDays <= 0 {
+		return ""
+	}
+	tol := a.cfg.StableTolerance
+	if tol <= 0 {
+		tol = a.fromKmh(defaultStableTolerance)
+	}
+	n, easterly := stableRun(days, a.cfg.MinEasterlySpeed, tol)
+	if n < a.cfg.StableDays {
+		return ""
+	}
+	through := a.tr.Day(days[n-1].Date)
+	if easterly {
+		return withMarker(a.tr.T(msgStableEasterly, through), a.cfg.Theme.Easterly)
+	}
+	return a.tr.T(msgStableWesterly, through)
+}
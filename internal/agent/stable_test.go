@@ -0,0 +1,57 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// windDays builds consecutive days from testNow with the given max speeds
+// and directions.
+func windDays(speeds, dirs []float64) []weather.ForecastDay {
+	days := make([]weather.ForecastDay, len(speeds))
+	for i := range speeds {
+		days[i] = weather.ForecastDay{Date: testNow.AddDate(0, 0, i), WindSpeedMax: speeds[i], WindDirMean: dirs[i]}
+	}
+	return days
+}
+
+func TestStableRun(t *testing.T) {
+	west := []float64{270, 260, 280, 270}
+	tests := []struct {
+		name         string
+		days         []weather.ForecastDay
+		want         int
+		wantEasterly bool
+	}{
+		{"flat westerly", windDays([]float64{20, 22, 25, 21}, west), 4, false},
+		{"flat easterly", windDays([]float64{20, 22, 25}, []float64{90, 100, 80}), 3, true},
+		{"tolerance break", windDays([]float64{20, 25, 31, 22}, west), 2, false},
+		{"direction break", windDays([]float64{20, 22, 25, 21}, []float64{270, 260, 90, 270}), 2, false},
+		{"light easterly", windDays([]float64{3, 22}, []float64{90, 270}), 0, false},
+		{"empty", nil, 0, false},
+	}
+	for _, tt := range tests {
+		n, easterly := stableRun(tt.days, 5, 10)
+		if n != tt.want || easterly != tt.wantEasterly {
+			t.Errorf("%s: stableRun = %d, %v; want %d, %v", tt.name, n, easterly, tt.want, tt.wantEasterly)
+		}
+	}
+}
+
+func TestStableLine(t *testing.T) {
+	a := newTestAgent(t, Config{StableDays: 3})
+	flat := windDays([]float64{20, 22, 25, 40}, []float64{270, 260, 280, 270})
+	if got, want := a.stableLine(flat), "Stable westerly through "+a.tr.Day(flat[2].Date); !strings.HasPrefix(got, want) {
+		t.Errorf("stableLine = %q, want it to start %q", got, want)
+	}
+	// Two flat days fall short of StableDays.
+	short := windDays([]float64{20, 22, 40}, []float64{270, 260, 280})
+	if got := a.stableLine(short); got != "" {
+		t.Errorf("stableLine = %q, want none", got)
+	}
+	if got := New(Config{StableDays: 1}).cfg.StableDays; got != 2 {
+		t.Errorf("StableDays 1 became %d, want 2", got)
+	}
+}
@@ -0,0 +1,99 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// state is the small amount of data the agent persists between runs.
+type state struct {
+	// LastAlerts maps an alert condition to when it was last sent. A condition
+	// is removed once it clears so the next occurrence alerts immediately.
+	LastAlerts map[string]time.Time `json:"last_alerts,omitempty"`
+	// LastRuns maps a check name to when it last ran.
+	LastRuns map[string]time.Time `json:"last_runs,omitempty"`
+	// LastForecast is the wind forecast from the previous check, diffed to
+	// report what changed.
+	LastForecast []weather.ForecastDay `json:"last_forecast,omitempty"`
+	// Pinned maps a Telegram chat ID to the message the agent pinned there.
+	Pinned map[string]int `json:"pinned,omitempty"`
+}
+
+// loadState reads the state file at path. A missing file yields empty state.
+func loadState(path string) (*state, error) {
+	st := &state{}
+	if path == "" {
+		return st, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return st, fmt.Errorf("read state: %w", err)
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return &state{}, fmt.Errorf("decode state: %w", err)
+	}
+	return st, nil
+}
+
+// save writes the state atomically via a temp file and rename.
+func (s *state) save(path string) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode state: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".state-*")
+	if err != nil {
+		return fmt.Errorf("create temp state: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("close state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("replace state: %w", err)
+	}
+	return nil
+}
+
+// updateState applies fn to the in-memory state under the lock and persists it.
+func (a *Agent) updateState(fn func(*state)) error {
+	a.stateMu.Lock()
+	defer a.stateMu.Unlock()
+	fn(a.state)
+	return a.state.save(a.cfg.StatePath)
+}
+
+// PinnedMessage implements PinStore.
+func (a *Agent) PinnedMessage(chatID string) int {
+	a.stateMu.Lock()
+	defer a.stateMu.Unlock()
+	return a.state.Pinned[chatID]
+}
+
+// SetPinnedMessage implements PinStore.
+func (a *Agent) SetPinnedMessage(chatID string, messageID int) error {
+	return a.updateState(func(st *state) {
+		if st.Pinned == nil {
+			st.Pinned = make(map[string]int)
+		}
+		st.Pinned[chatID] = messageID
+	})
+}
@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// Streak is a run of consecutive easterly days.
+type Streak struct {
+	Start time.Time
+	Days  int
+}
+
+// End returns the date of the streak's last day.
+func (s Streak) End() time.Time {
+	return s.Start.AddDate(0, 0, s.Days-1)
+}
+
+// EasterlyStreaks returns each run of consecutive easterly days in order,
+// including single-day runs. A gap in the dates ends a run even when both
+// sides are easterly. minSpeed is as for Config.MinEasterlySpeed.
+func EasterlyStreaks(days []weather.ForecastDay, minSpeed float64) []Streak {
+	var streaks []Streak
+	for i, d := range days {
+		if !dayEasterly(d, minSpeed) {
+			continue
+		}
+		if n := len(streaks); n > 0 && i > 0 && dayEasterly(days[i-1], minSpeed) &&
+			sameDay(streaks[n-1].End().AddDate(0, 0, 1), d.Date) {
+			streaks[n-1].Days++
+			continue
+		}
+		streaks = append(streaks, Streak{Start: d.Date, Days: 1})
+	}
+	return streaks
+}
+
+// longestStreak returns the first of the longest streaks, and false when
+// there are none.
+func longestStreak(streaks []Streak) (Streak, bool) {
+	var best Streak
+	for _, s := range streaks {
+		if s.Days > best.Days {
+			best = s
+		}
+	}
+	return best, best.Days > 0
+}
+
+// streakRange names a streak's weekdays ("Mon–Thu", or "Sat" for one day).
+func streakRange(s Streak, tr translator) string {
+	start := weekdayNames[tr.lang][s.Start.Weekday()]
+	if s.Days == 1 {
+		return start
+	}
+	return fmt.Sprintf("%s–%s", start, weekdayNames[tr.lang][s.End().Weekday()])
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,96 @@
+package agent
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// dirDays returns one day per direction from Monday 6 January 2025.
+func dirDays(dirs ...float64) []weather.ForecastDay {
+	days := make([]weather.ForecastDay, len(dirs))
+	for i, dir := range dirs {
+		days[i] = weather.ForecastDay{Date: time.Date(2025, 1, 6+i, 0, 0, 0, 0, time.UTC), WindDirMean: dir}
+	}
+	return days
+}
+
+func TestEasterlyStreaks(t *testing.T) {
+	gap := dirDays(90, 90, 90)
+	gap[2].Date = gap[2].Date.AddDate(0, 0, 1)
+	light := dirDays(90, 90, 90, 90)
+	for i, speed := range []float64{20, 5, 20, 20} {
+		light[i].WindSpeedMax = speed
+	}
+
+	tests := []struct {
+		name     string
+		days     []weather.ForecastDay
+		minSpeed float64
+		want     []int // streak lengths
+	}{
+		{"none", dirDays(270, 250, 300), 0, nil},
+		{"single days", dirDays(90, 270, 100, 270), 0, []int{1, 1}},
+		{"runs", dirDays(90, 100, 270, 45, 60, 120), 0, []int{2, 3}},
+		{"date gap", gap, 0, []int{2, 1}},
+		{"cut by the forecast window", dirDays(270, 90, 90, 90, 90)[:3], 0, []int{2}},
+		{"light day breaks a run", light, 10, []int{1, 2}},
+		{"no minimum speed", light, 0, []int{4}},
+	}
+	for _, tt := range tests {
+		var got []int
+		for _, s := range EasterlyStreaks(tt.days, tt.minSpeed) {
+			got = append(got, s.Days)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: streak lengths %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLongestStreakLine(t *testing.T) {
+	tr := newTranslator("en")
+	tests := []struct {
+		days []weather.ForecastDay
+		want string
+	}{
+		{dirDays(270, 90, 270), "Longest easterly streak: Tue, 1 day"},
+		{dirDays(90, 270, 90, 90, 90, 90), "Longest easterly streak: Wed–Sat, 4 days"},
+	}
+	for _, tt := range tests {
+		if got := buildEasterlyAnalysis(tt.days, 0, 0, DefaultTheme.Easterly, tr); !strings.Contains(got, tt.want) {
+			t.Errorf("analysis %q lacks %q", got, tt.want)
+		}
+	}
+	if got := buildEasterlyAnalysis(dirDays(270, 250), 0, 0, DefaultTheme.Easterly, tr); strings.Contains(got, "streak") {
+		t.Errorf("analysis %q mentions a streak without easterly days", got)
+	}
+}
+
+func TestDominantMargin(t *testing.T) {
+	tr := newTranslator("en")
+	const e, w = 90, 270
+	tests := []struct {
+		name   string
+		days   []weather.ForecastDay
+		margin int
+		want   string
+	}{
+		{"any majority", dirDays(e, e, e, w, w), 0, "Dominant: E ✈️ | East: 3 days (60%) | West: 2 days (40%)"},
+		{"tie", dirDays(e, w), 0, "Dominant: Mixed | East: 1 days (50%) | West: 1 days (50%)"},
+		{"less than the margin", dirDays(e, e, e, e, w, w, w), 2, "Dominant: Mostly E, some W | East: 4 days (57%) | West: 3 days (43%)"},
+		{"exactly the margin", dirDays(e, e, e, e, w, w), 2, "Dominant: Mostly E, some W | East: 4 days (67%) | West: 2 days (33%)"},
+		{"more than the margin", dirDays(e, e, e, e, e, w, w), 2, "Dominant: E ✈️ | East: 5 days (71%) | West: 2 days (29%)"},
+		{"west within the margin", dirDays(e, e, w, w, w, w), 2, "Dominant: Mostly W, some E | East: 2 days (33%) | West: 4 days (67%)"},
+		{"west beyond the margin", dirDays(e, w, w, w, w), 2, "Dominant: W | East: 1 days (20%) | West: 4 days (80%)"},
+	}
+	for _, tt := range tests {
+		got, _, _ := strings.Cut(buildEasterlyAnalysis(tt.days, tt.margin, 0, DefaultTheme.Easterly, tr), "\n")
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// Events that Config.SunWindEvent can be relative to.
+const (
+	SunEventSunrise = "sunrise"
+	SunEventSunset  = "sunset"
+)
+
+// sunWindLines reports the wind at SunWindOffset from each day's sunrise or
+// sunset within the confidence horizon, e.g. "Dawn wind (sunrise-1h):" then
+// "Thu 16 Jan 06:12: light E 8 km/h". Days without the event (polar day or
+// night) say so instead.
+func (a *Agent) sunWindLines(ctx context.Context, days []weather.ForecastDay) (string, error) {
+	if a.cfg.SunWindEvent == "" || len(days) == 0 {
+		return "", nil
+	}
+	hourly, err := a.cfg.WindWeather.FetchHourlyWind(ctx, len(days))
+	if err != nil {
+		return "", fmt.Errorf("fetch hourly wind for %s: %w", a.cfg.SunWindEvent, err)
+	}
+
+	tr := a.tr
+	header := msgDawnWind
+	if a.cfg.SunWindEvent == SunEventSunset {
+		header = msgDuskWind
+	}
+	lines := []string{tr.T(header, a.cfg.SunWindEvent+offsetLabel(a.cfg.SunWindOffset))}
+	for _, d := range days {
+		if a.beyondHorizon(d) {
+			break
+		}
+		event := d.Sunrise
+		if a.cfg.SunWindEvent == SunEventSunset {
+			event = d.Sunset
+		}
+		if event.IsZero() {
+			lines = append(lines, tr.T(msgSunWindNone, tr.Day(d.Date), a.cfg.SunWindEvent))
+			continue
+		}
+		at := event.Add(a.cfg.SunWindOffset)
+		w, ok := weather.WindAt(hourly, at)
+		if !ok {
+			continue
+		}
+		lines = append(lines, tr.T(msgSunWindDay, tr.Day(d.Date), at.Format("15:04"),
+			windStrength(a.kmh(w.Speed), tr), degToCompass(w.Direction, tr), w.Speed, a.windSymbol()))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// windStrength names a speed (km/h) on a coarse Beaufort-based scale.
+func windStrength(kmh float64, tr translator) string {
+	switch {
+	case kmh < 2:
+		return tr.T(msgCalm)
+	case kmh < 20:
+		return tr.T(msgLight)
+	case kmh < 39:
+		return tr.T(msgModerate)
+	default:
+		return tr.T(msgStrong)
+	}
+}
+
+// offsetLabel formats an offset as "-1h", "+30m" or "" for none.
+func offsetLabel(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	s := d.String() // e.g. "-1h0m0s"
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	if d > 0 {
+		s = "+" + s
+	}
+	return s
+}
@@ -0,0 +1,38 @@
+package agent
+
+import (
+	"math"
+	"strings"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// directionSwings flags consecutive days whose dominant directions differ by
+// more than DirectionSwingThreshold degrees, typically a front passing, e.g.
+// "⚠ Wind veering sharply Wed→Thu (250°→10°, 120°)". Days with an unknown
+// direction or a gap between them are not compared.
+func (a *Agent) directionSwings(days []weather.ForecastDay) string {
+	if a.cfg.DirectionSwingThreshold <= 0 {
+		return ""
+	}
+	tr := a.tr
+	var lines []string
+	for i := 1; i < len(days); i++ {
+		prev, curr := days[i-1], days[i]
+		if prev.DirUnknown || curr.DirUnknown || !sameDay(prev.Date.AddDate(0, 0, 1), curr.Date) {
+			continue
+		}
+		turn := weather.Turn(prev.WindDirMean, curr.WindDirMean)
+		if math.Abs(turn) <= a.cfg.DirectionSwingThreshold {
+			continue
+		}
+		key := msgVeering
+		if turn < 0 {
+			key = msgBacking
+		}
+		names := weekdayNames[tr.lang]
+		lines = append(lines, tr.T(key, names[prev.Date.Weekday()], names[curr.Date.Weekday()],
+			prev.WindDirMean, curr.WindDirMean, math.Abs(turn)))
+	}
+	return strings.Join(lines, "\n")
+}
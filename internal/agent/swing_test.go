@@ -0,0 +1,37 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+func TestDirectionSwings(t *testing.T) {
+	gap := dirDays(250, 10)
+	gap[1].Date = gap[1].Date.AddDate(0, 0, 1)
+	unknown := dirDays(250, 10)
+	unknown[1].DirUnknown = true
+
+	tests := []struct {
+		name      string
+		days      []weather.ForecastDay
+		threshold float64
+		want      string
+	}{
+		{"veering across north", dirDays(350, 10), 15, "⚠ Wind veering sharply Mon→Tue (350°→10°, 20°)"},
+		{"backing across north", dirDays(10, 350), 15, "⚠ Wind backing sharply Mon→Tue (10°→350°, 20°)"},
+		{"at the threshold", dirDays(350, 10), 20, ""},
+		{"veering", dirDays(250, 10), 90, "⚠ Wind veering sharply Mon→Tue (250°→10°, 120°)"},
+		{"backing", dirDays(90, 300), 90, "⚠ Wind backing sharply Mon→Tue (90°→300°, 150°)"},
+		{"opposite counts as veering", dirDays(0, 180), 90, "⚠ Wind veering sharply Mon→Tue (0°→180°, 180°)"},
+		{"off", dirDays(250, 10), 0, ""},
+		{"date gap", gap, 90, ""},
+		{"unknown direction", unknown, 90, ""},
+	}
+	for _, tt := range tests {
+		a := newTestAgent(t, Config{DirectionSwingThreshold: tt.threshold})
+		if got := a.directionSwings(tt.days); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package agent
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// golden compares got with testdata/name, rewriting the file with -update.
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%s mismatch (rerun with -update to accept)\ngot:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+// sampleForecast is eight days from Monday 6 January 2025, mixing easterly,
+// westerly and light easterly days, with gusts and temperatures.
+func sampleForecast() []weather.ForecastDay {
+	rows := []struct {
+		speed, gust, dir, lo, hi float64
+	}{
+		{25, 40, 90, 2, 8},
+		{18, 31, 270, 3, 9},
+		{32, 58, 100, 1, 7},
+		{8, 15, 80, 0, 5},
+		{104, 130, 250, 4, 11},
+		{22, 35, 260, 5, 12},
+		{12, 20, 45, 3, 10},
+		{27, 44, 120, 2, 6},
+	}
+	days := make([]weather.ForecastDay, len(rows))
+	for i, r := range rows {
+		days[i] = weather.ForecastDay{
+			Date:         time.Date(2025, 1, 6+i, 0, 0, 0, 0, time.UTC),
+			WindSpeedMax: r.speed,
+			WindGustMax:  r.gust,
+			WindDirMean:  r.dir,
+			TempMin:      r.lo,
+			TempMax:      r.hi,
+		}
+	}
+	return days
+}
+
+func TestForecastTableGolden(t *testing.T) {
+	tests := []struct {
+		file string
+		cfg  Config
+	}{
+		{"table_date.golden", Config{}},
+		{"table_date_desc.golden", Config{TableSort: TableSortDateDesc}},
+		{"table_nearest.golden", Config{TableSort: TableSortNearest}},
+		{"table_wind.golden", Config{TableSort: TableSortWind}},
+		{"table_columns.golden", Config{ShowTemperature: true, GustMarkerThreshold: 50, MinEasterlySpeed: 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			tt.cfg.Now = func() time.Time { return testNow }
+			tt.cfg.WindWeather = &weather.OpenMeteoClient{}
+			golden(t, tt.file, New(tt.cfg).buildForecastTable(sampleForecast()))
+		})
+	}
+}
+
+func TestGustMarker(t *testing.T) {
+	a := New(Config{
+		GustMarkerThreshold: 44,
+		TableColumns:        []string{ColumnDate, ColumnGust},
+		Now:                 func() time.Time { return testNow },
+		WindWeather:         &weather.OpenMeteoClient{},
+	})
+	days := sampleForecast()
+	lines := strings.Split(a.buildForecastTable(days), "\n")[2:]
+	for i, d := range days {
+		// Gusts equal to the threshold are marked too.
+		want := d.WindGustMax >= 44
+		if got := strings.Contains(lines[i], DefaultTheme.Gust); got != want {
+			t.Errorf("%s (gusts %v): marked %v, want %v", d.Date.Format("Mon 02"), d.WindGustMax, got, want)
+		}
+	}
+}
+
+func TestBeyondHorizonFromToday(t *testing.T) {
+	a := New(Config{Now: func() time.Time { return testNow }, WindWeather: &weather.OpenMeteoClient{}})
+	// A forecast starting three days out, as with StartOffsetDays 3: the
+	// horizon still counts from today, Monday 6 January.
+	days := sampleForecast()
+	for i := range days {
+		days[i].Date = days[i].Date.AddDate(0, 0, 3)
+	}
+	for _, d := range days {
+		want := !d.Date.Before(time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC))
+		if got := a.beyondHorizon(d); got != want {
+			t.Errorf("%s: beyondHorizon = %v, want %v", d.Date.Format("Mon 02 Jan"), got, want)
+		}
+	}
+}
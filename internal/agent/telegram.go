@@ -0,0 +1,393 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/emanuelefumagalli/test-agent/internal/httpx"
+)
+
+const defaultTelegramBaseURL = "https://api.telegram.org"
+
+// Telegram parse modes. parseModePlain is sent as an omitted parse_mode so
+// Telegram treats the text literally.
+const (
+	parseModeMarkdown   = "Markdown"
+	parseModeMarkdownV2 = "MarkdownV2"
+	parseModeHTML       = "HTML"
+	parseModePlain      = ""
+)
+
+// normalizeParseMode maps user input to a Telegram parse mode, defaulting to
+// legacy Markdown for empty or unknown values.
+func normalizeParseMode(mode string) string {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", "markdown":
+		return parseModeMarkdown
+	case "markdownv2":
+		return parseModeMarkdownV2
+	case "html":
+		return parseModeHTML
+	case "plain", "none", "text":
+		return parseModePlain
+	default:
+		fmt.Printf("warning: unknown Telegram parse mode %q, using Markdown\n", mode)
+		return parseModeMarkdown
+	}
+}
+
+// TelegramNotifier sends reports through the Telegram Bot API, fencing the
+// table as a code block when the parse mode allows it.
+type TelegramNotifier struct {
+	Token string
+	// ChatID is a numeric chat ID, or "@username" for a public channel the
+	// bot is an administrator of.
+	ChatID    string
+	BaseURL   string // defaults to https://api.telegram.org
+	ParseMode string // already normalized, see normalizeParseMode
+	// HTTPClient defaults to a shared client sending httpx.DefaultUserAgent.
+	HTTPClient *http.Client
+	// SeparateSummary sends the Ollama summary as its own message after the
+	// table instead of combining them.
+	SeparateSummary bool
+	// PinKind pins the first message of each report of this Kind (e.g.
+	// "wind") and unpins the previously pinned one. Empty disables pinning.
+	PinKind string
+	// Pins remembers the pinned message across restarts. Required for
+	// unpinning when PinKind is set.
+	Pins PinStore
+}
+
+// PinStore remembers the message pinned in each chat.
+type PinStore interface {
+	PinnedMessage(chatID string) int
+	SetPinnedMessage(chatID string, messageID int) error
+}
+
+// telegramMaxLen is the Bot API's limit on message text, in characters.
+const telegramMaxLen = 4096
+
+// Name implements Notifier.
+func (t *TelegramNotifier) Name() string { return "telegram" }
+
+// Notify implements Notifier. The report goes out as one message unless
+// SeparateSummary is set or it exceeds Telegram's length limit.
+func (t *TelegramNotifier) Notify(ctx context.Context, r Report) error {
+	for i, msg := range t.messages(r) {
+		id, err := t.send(ctx, t.ChatID, msg)
+		if err != nil {
+			return err
+		}
+		if i == 0 && t.PinKind != "" && r.Kind == t.PinKind {
+			t.pin(ctx, id)
+		}
+	}
+	return nil
+}
+
+// pin pins messageID and unpins the message pinned before it. Pinning is a
+// nicety, so failures such as a bot without pin rights are only warned about.
+func (t *TelegramNotifier) pin(ctx context.Context, messageID int) {
+	err := t.call(ctx, "pinChatMessage", map[string]any{
+		"chat_id":              t.ChatID,
+		"message_id":           messageID,
+		"disable_notification": true,
+	}, nil)
+	if err != nil {
+		fmt.Printf("warning: pin telegram message: %v\n", err)
+		return
+	}
+	if t.Pins == nil {
+		return
+	}
+	if prev := t.Pins.PinnedMessage(t.ChatID); prev != 0 && prev != messageID {
+		err := t.call(ctx, "unpinChatMessage", map[string]any{
+			"chat_id":    t.ChatID,
+			"message_id": prev,
+		}, nil)
+		if err != nil {
+			fmt.Printf("warning: unpin telegram message %d: %v\n", prev, err)
+		}
+	}
+	if err := t.Pins.SetPinnedMessage(t.ChatID, messageID); err != nil {
+		fmt.Printf("warning: save pinned message: %v\n", err)
+	}
+}
+
+// messages splits a report into the messages to send. Oversized reports are
+// cut at line boundaries, with the table re-fenced in every piece.
+func (t *TelegramNotifier) messages(r Report) []string {
+	tr := TelegramRenderer{ParseMode: t.ParseMode}
+	if !t.SeparateSummary {
+		if msg := tr.text(r); utf8.RuneCountInString(msg) <= telegramMaxLen {
+			return []string{msg}
+		}
+	}
+
+	// Leave room for the code fence or <pre> tags around table pieces.
+	const fenceRoom = 16
+	var report []string
+	report = append(report, splitLines(tr.escape(strings.TrimRight(r.Headline, "\n")), telegramMaxLen)...)
+	for _, chunk := range splitLines(r.Table, telegramMaxLen-fenceRoom) {
+		report = append(report, tr.formatTable(chunk+"\n"))
+	}
+	summary := splitLines(tr.escape(r.Summary), telegramMaxLen)
+
+	var msgs []string
+	if t.SeparateSummary {
+		msgs = append(packLines(report), packLines(summary)...)
+	} else {
+		msgs = packLines(append(report, summary...))
+	}
+	if footer := tr.escape(r.Footer); footer != "" {
+		if last := len(msgs) - 1; last >= 0 && utf8.RuneCountInString(msgs[last])+1+utf8.RuneCountInString(footer) <= telegramMaxLen {
+			msgs[last] += "\n" + footer
+		} else {
+			msgs = append(msgs, footer)
+		}
+	}
+	return msgs
+}
+
+// splitLines cuts text into pieces of at most limit characters, breaking at
+// newlines where possible.
+func splitLines(text string, limit int) []string {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return nil
+	}
+	var pieces []string
+	var cur []rune
+	for _, line := range strings.Split(text, "\n") {
+		runes := []rune(line)
+		for len(runes) > limit {
+			if len(cur) > 0 {
+				pieces = append(pieces, string(cur))
+				cur = nil
+			}
+			pieces = append(pieces, string(runes[:limit]))
+			runes = runes[limit:]
+		}
+		if len(cur) > 0 && len(cur)+1+len(runes) > limit {
+			pieces = append(pieces, string(cur))
+			cur = nil
+		}
+		if len(cur) > 0 {
+			cur = append(cur, '\n')
+		}
+		cur = append(cur, runes...)
+	}
+	if len(cur) > 0 {
+		pieces = append(pieces, string(cur))
+	}
+	return pieces
+}
+
+// packLines joins consecutive pieces with newlines into as few messages as
+// fit the limit.
+func packLines(pieces []string) []string {
+	var msgs []string
+	for _, p := range pieces {
+		if last := len(msgs) - 1; last >= 0 && utf8.RuneCountInString(msgs[last])+1+utf8.RuneCountInString(p) <= telegramMaxLen {
+			msgs[last] += "\n" + p
+			continue
+		}
+		msgs = append(msgs, p)
+	}
+	return msgs
+}
+
+// TelegramRenderer lays a report out as one Telegram message in ParseMode,
+// with the table in a code block. TelegramNotifier uses it, splitting
+// reports that exceed Telegram's length limit.
+type TelegramRenderer struct {
+	ParseMode string
+}
+
+// Render implements Renderer.
+func (t TelegramRenderer) Render(w io.Writer, r Report) error {
+	_, err := io.WriteString(w, t.text(r)+"\n")
+	return err
+}
+
+// text lays the report out for the configured parse mode.
+func (t TelegramRenderer) text(r Report) string {
+	var table string
+	if r.Table != "" {
+		table = t.formatTable(r.Table)
+	}
+	return joinNonEmpty("\n",
+		t.escape(strings.TrimRight(r.Headline, "\n")),
+		table,
+		t.escape(r.Summary),
+		t.escape(r.Footer),
+	)
+}
+
+// formatTable wraps the table in a code block when the parse mode supports
+// one, and sends it verbatim otherwise.
+func (t TelegramRenderer) formatTable(table string) string {
+	switch t.ParseMode {
+	case parseModeMarkdown, parseModeMarkdownV2:
+		return "```\n" + table + "```"
+	case parseModeHTML:
+		return "<pre>" + html.EscapeString(table) + "</pre>"
+	default:
+		return table
+	}
+}
+
+// markdownV2Escaper escapes the characters MarkdownV2 reserves outside code.
+var markdownV2Escaper = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+	"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`,
+	"=", `\=`, "|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+// escape escapes free text for the configured parse mode. Legacy Markdown
+// and plain text are sent as-is.
+func (t TelegramRenderer) escape(text string) string {
+	switch t.ParseMode {
+	case parseModeHTML:
+		return html.EscapeString(text)
+	case parseModeMarkdownV2:
+		return markdownV2Escaper.Replace(text)
+	default:
+		return text
+	}
+}
+
+// TelegramMessage is the payload for Telegram API
+type TelegramMessage struct {
+	ChatID    string `json:"chat_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode,omitempty"`
+}
+
+// send posts one message and returns its message ID.
+func (t *TelegramNotifier) send(ctx context.Context, chatID, message string) (int, error) {
+	msg := TelegramMessage{
+		ChatID:    chatID,
+		Text:      message,
+		ParseMode: t.ParseMode,
+	}
+	var sent struct {
+		MessageID int `json:"message_id"`
+	}
+	if err := t.call(ctx, "sendMessage", msg, &sent); err != nil {
+		return 0, chatError(chatID, err)
+	}
+	return sent.MessageID, nil
+}
+
+// ErrTelegramChannel reports that the bot may not post to the channel it was
+// pointed at; the error text says how to fix it.
+var ErrTelegramChannel = errors.New("telegram bot can't post to the channel")
+
+// telegramAPIError is a Bot API call Telegram rejected, with its reason.
+type telegramAPIError struct {
+	Status      int
+	Description string
+}
+
+func (e *telegramAPIError) Error() string {
+	return fmt.Sprintf("telegram API returned status %d: %s", e.Status, e.Description)
+}
+
+// chatError turns Telegram's replies for a channel the bot can't post to
+// into ErrTelegramChannel with a hint, leaving other errors alone.
+func chatError(chatID string, err error) error {
+	var apiErr *telegramAPIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	switch {
+	case strings.Contains(apiErr.Description, "not a member of the channel chat"),
+		strings.Contains(apiErr.Description, "need administrator rights in the channel chat"):
+		return fmt.Errorf("%w %s: add the bot as a channel administrator allowed to post messages: %w", ErrTelegramChannel, chatID, err)
+	case strings.HasPrefix(chatID, "@") && strings.Contains(apiErr.Description, "chat not found"):
+		return fmt.Errorf("%w %s: no such public channel, check the username: %w", ErrTelegramChannel, chatID, err)
+	}
+	return err
+}
+
+// validTelegramChatID reports whether id is a numeric chat ID or a public
+// "@username" (5-32 letters, digits and underscores).
+func validTelegramChatID(id string) bool {
+	if name, ok := strings.CutPrefix(id, "@"); ok {
+		return len(name) >= 5 && len(name) <= 32 && strings.Trim(name, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_") == ""
+	}
+	_, err := strconv.ParseInt(id, 10, 64)
+	return err == nil
+}
+
+// call invokes a Bot API method with a JSON payload and, when result is
+// non-nil, decodes the response's "result" field into it.
+func (t *TelegramNotifier) call(ctx context.Context, method string, payload, result any) error {
+	baseURL := t.BaseURL
+	if baseURL == "" {
+		baseURL = defaultTelegramBaseURL
+	}
+	url := fmt.Sprintf("%s/bot%s/%s", strings.TrimRight(baseURL, "/"), t.Token, method)
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal telegram %s: %w", method, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create telegram request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	client := t.HTTPClient
+	if client == nil {
+		client = httpx.NewClient(10*time.Second, "")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send telegram %s: %w", method, err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			fmt.Printf("warning: close telegram response body: %v\n", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		var failure struct {
+			Description string `json:"description"`
+		}
+		if json.Unmarshal(body, &failure) == nil && failure.Description != "" {
+			return &telegramAPIError{Status: resp.StatusCode, Description: failure.Description}
+		}
+		return fmt.Errorf("telegram API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	if result == nil {
+		return nil
+	}
+	var envelope struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("decode telegram %s response: %w", method, err)
+	}
+	if err := json.Unmarshal(envelope.Result, result); err != nil {
+		return fmt.Errorf("decode telegram %s result: %w", method, err)
+	}
+	return nil
+}
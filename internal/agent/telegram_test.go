@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// rejectingTelegram answers every call with status and a Bot API
+// description, and returns its URL.
+func rejectingTelegram(t *testing.T, status int, description string) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = fmt.Fprintf(w, `{"ok":false,"error_code":%d,"description":%q}`, status, description)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestSendChannelErrors(t *testing.T) {
+	tests := []struct {
+		name, chat, description string
+		status                  int
+		channel                 bool
+	}{
+		{"not a member", "@weather_news", "Forbidden: bot is not a member of the channel chat", http.StatusForbidden, true},
+		{"not an admin", "-1001234567890", "Bad Request: need administrator rights in the channel chat", http.StatusBadRequest, true},
+		{"unknown channel", "@weather_news", "Bad Request: chat not found", http.StatusBadRequest, true},
+		{"unknown numeric chat", "12345", "Bad Request: chat not found", http.StatusBadRequest, false},
+		{"blocked", "12345", "Forbidden: bot was blocked by the user", http.StatusForbidden, false},
+	}
+	for _, tt := range tests {
+		bot := &TelegramNotifier{Token: "token", BaseURL: rejectingTelegram(t, tt.status, tt.description)}
+		_, err := bot.send(context.Background(), tt.chat, "hi")
+		if got := errors.Is(err, ErrTelegramChannel); got != tt.channel {
+			t.Errorf("%s: send error %v, ErrTelegramChannel %v, want %v", tt.name, err, got, tt.channel)
+		}
+		var apiErr *telegramAPIError
+		if !errors.As(err, &apiErr) || apiErr.Status != tt.status || apiErr.Description != tt.description {
+			t.Errorf("%s: send error %v doesn't wrap Telegram's reply", tt.name, err)
+		}
+	}
+}
+
+func TestValidTelegramChatID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"12345", true},
+		{"-1001234567890", true},
+		{"@weather_news", true},
+		{"@abcd", false},
+		{"@" + "abcdefghijklmnopqrstuvwxyz0123456", false},
+		{"@weather-news", false},
+		{"weather_news", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := validTelegramChatID(tt.id); got != tt.want {
+			t.Errorf("validTelegramChatID(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
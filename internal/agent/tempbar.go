@@ -0,0 +1,42 @@
+package agent
+
+import (
+	"math"
+	"strings"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// tempBarWidth is the number of characters in a temperature range bar.
+const tempBarWidth = 10
+
+// TempBar renders the span lo..hi as an ASCII bar, e.g. "..=====...",
+// placed on a scale running from floor to ceil. Every span draws at least one
+// "="; when floor equals ceil (all temperatures the same) the bar is full.
+func TempBar(lo, hi, floor, ceil float64) string {
+	if ceil <= floor {
+		return strings.Repeat("=", tempBarWidth)
+	}
+	pos := func(v float64) int {
+		p := int(math.Round((v - floor) / (ceil - floor) * (tempBarWidth - 1)))
+		return min(max(p, 0), tempBarWidth-1)
+	}
+	start, end := pos(min(lo, hi)), pos(max(lo, hi))
+	return strings.Repeat(".", start) + strings.Repeat("=", end-start+1) + strings.Repeat(".", tempBarWidth-1-end)
+}
+
+// tempScale returns the lowest min and highest max temperature across days,
+// and false when no day has both.
+func tempScale(days []weather.ForecastDay) (floor, ceil float64, ok bool) {
+	for _, d := range days {
+		if !hasTemperature(d) {
+			continue
+		}
+		if !ok {
+			floor, ceil, ok = d.TempMin, d.TempMax, true
+			continue
+		}
+		floor, ceil = min(floor, d.TempMin), max(ceil, d.TempMax)
+	}
+	return floor, ceil, ok
+}
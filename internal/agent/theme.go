@@ -0,0 +1,110 @@
+package agent
+
+// Theme maps the conditions reports flag to the markers drawn for them.
+// Emoji read badly through screen readers and some terminals draw them
+// double width, misaligning monospace tables; PlainTheme avoids both. An
+// empty marker draws nothing.
+type Theme struct {
+	// Wind strength by a day's max speed, on the Beaufort scale: Calm up to
+	// force 3 (<20 km/h), Moderate 4-5 (<39), Windy 6-7 (<62), Severe 8+.
+	// Drawn after the speed in the forecast table.
+	Calm     string
+	Moderate string
+	Windy    string
+	Severe   string
+
+	// Rain chance: Dry under 30%, MaybeRain from 30%, Rain from 70%. The
+	// rain table flags 30% and up with Rain.
+	Dry       string
+	MaybeRain string
+	Rain      string
+
+	// Direction: Easterly days mean planes overhead; LightEasterly ones are
+	// below Config.MinEasterlySpeed.
+	Easterly      string
+	LightEasterly string
+	Westerly      string
+
+	// Gust flags gusts at the marker or alert threshold.
+	Gust string
+}
+
+// DefaultTheme is the emoji theme used unless configured otherwise.
+var DefaultTheme = Theme{
+	Dry:           "☀️",
+	MaybeRain:     "🌦️",
+	Rain:          "☔",
+	Easterly:      "✈️",
+	LightEasterly: "E?",
+	Gust:          "⚠",
+}
+
+// PlainTheme is an ASCII equivalent of DefaultTheme.
+var PlainTheme = Theme{
+	Dry:           "DRY",
+	MaybeRain:     "MAYBE",
+	Rain:          "RAIN",
+	Easterly:      "EAST",
+	LightEasterly: "E?",
+	Gust:          "GUST",
+}
+
+// withDefaults fills empty markers from DefaultTheme.
+func (t Theme) withDefaults() Theme {
+	for _, f := range []struct {
+		dst *string
+		def string
+	}{
+		{&t.Calm, DefaultTheme.Calm},
+		{&t.Moderate, DefaultTheme.Moderate},
+		{&t.Windy, DefaultTheme.Windy},
+		{&t.Severe, DefaultTheme.Severe},
+		{&t.Dry, DefaultTheme.Dry},
+		{&t.MaybeRain, DefaultTheme.MaybeRain},
+		{&t.Rain, DefaultTheme.Rain},
+		{&t.Easterly, DefaultTheme.Easterly},
+		{&t.LightEasterly, DefaultTheme.LightEasterly},
+		{&t.Westerly, DefaultTheme.Westerly},
+		{&t.Gust, DefaultTheme.Gust},
+	} {
+		if *f.dst == "" {
+			*f.dst = f.def
+		}
+	}
+	return t
+}
+
+// windMarker returns the marker for a max wind speed (km/h).
+func (t Theme) windMarker(kmh float64) string {
+	switch {
+	case kmh < 20:
+		return t.Calm
+	case kmh < 39:
+		return t.Moderate
+	case kmh < 62:
+		return t.Windy
+	default:
+		return t.Severe
+	}
+}
+
+// rainMarker returns the marker for a rain chance (%).
+func (t Theme) rainMarker(prob int) string {
+	switch {
+	case prob >= 70:
+		return t.Rain
+	case prob >= 30:
+		return t.MaybeRain
+	default:
+		return t.Dry
+	}
+}
+
+// withMarker appends marker to s after a space, or returns s when marker is
+// empty.
+func withMarker(s, marker string) string {
+	if marker == "" {
+		return s
+	}
+	return s + " " + marker
+}
@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"strings"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// directionTimeline describes only where the wind flips between easterly and
+// westerly, e.g. "W until Wed, then E Thu–Sat, back to W Sun". Days with an
+// unknown direction continue the current run.
+func directionTimeline(days []weather.ForecastDay, minSpeed float64, tr translator) string {
+	if len(days) == 0 {
+		return tr.T(msgNoData)
+	}
+
+	type run struct {
+		easterly bool
+		span     Streak
+	}
+	var runs []run
+	for i, d := range days {
+		if len(runs) > 0 && (d.DirUnknown || dayEasterly(d, minSpeed) == runs[len(runs)-1].easterly) {
+			runs[len(runs)-1].span.Days++
+			continue
+		}
+		easterly := dayEasterly(d, minSpeed)
+		if d.DirUnknown && i+1 < len(days) {
+			// A leading unknown day takes the direction of the next one.
+			easterly = dayEasterly(days[i+1], minSpeed)
+		}
+		runs = append(runs, run{easterly: easterly, span: Streak{Start: d.Date, Days: 1}})
+	}
+
+	name := func(easterly bool) string {
+		if easterly {
+			return tr.T(msgEast)
+		}
+		return tr.T(msgWest)
+	}
+	if len(runs) == 1 {
+		return tr.T(msgTimelineAll, name(runs[0].easterly))
+	}
+	first := runs[0].span
+	parts := []string{tr.T(msgTimelineUntil, name(runs[0].easterly), weekdayNames[tr.lang][first.End().Weekday()])}
+	for i, r := range runs[1:] {
+		key := msgTimelineBack
+		if i == 0 {
+			key = msgTimelineThen
+		}
+		parts = append(parts, tr.T(key, name(r.easterly), streakRange(r.span, tr)))
+	}
+	return strings.Join(parts, ", ")
+}
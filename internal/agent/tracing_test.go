@@ -0,0 +1,76 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRunTracing(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	n := &recordingNotifier{name: "test", err: errors.New("boom")}
+	a := newTestAgent(t, Config{TracerProvider: tp, Notifiers: []Notifier{n}})
+	if err := a.RunOnce(context.Background()); err == nil {
+		t.Fatal("RunOnce succeeded, want the notifier's failure")
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range rec.Ended() {
+		if _, ok := spans[s.Name()]; !ok {
+			spans[s.Name()] = s
+		}
+	}
+	root, ok := spans["daily_run"]
+	if !ok {
+		t.Fatalf("no daily_run span among %d spans", len(rec.Ended()))
+	}
+	if root.Parent().IsValid() {
+		t.Error("daily_run has a parent, want a root span")
+	}
+	if root.Status().Code != codes.Error {
+		t.Errorf("daily_run status %v, want Error", root.Status().Code)
+	}
+
+	parents := map[string]string{
+		"wind_check":     "daily_run",
+		"rain_check":     "daily_run",
+		"wind fetch":     "wind_check",
+		"rain fetch":     "rain_check",
+		"ollama summary": "wind_check",
+		"notify":         "wind_check",
+	}
+	for name, parent := range parents {
+		s, ok := spans[name]
+		if !ok {
+			t.Errorf("no %s span", name)
+			continue
+		}
+		if s.SpanContext().TraceID() != root.SpanContext().TraceID() {
+			t.Errorf("%s is in another trace", name)
+		}
+		if got := s.Parent().SpanID(); got != spans[parent].SpanContext().SpanID() {
+			t.Errorf("%s parent is %v, want %s", name, got, parent)
+		}
+	}
+
+	if got := spans["wind_check"].Attributes(); !slices.Contains(got, attribute.String("location", "Heathrow")) {
+		t.Errorf("wind_check attributes %v, want location Heathrow", got)
+	}
+	if got := spans["wind fetch"].Attributes(); !slices.Contains(got, attribute.Int("attempts", 1)) {
+		t.Errorf("wind fetch attributes %v, want 1 attempt", got)
+	}
+	notify := spans["notify"]
+	if notify.Status().Code != codes.Error || notify.Status().Description != "boom" {
+		t.Errorf("notify status %+v, want Error boom", notify.Status())
+	}
+	if !slices.Contains(notify.Attributes(), attribute.String("notifier", "test")) {
+		t.Errorf("notify attributes %v, want notifier test", notify.Attributes())
+	}
+}
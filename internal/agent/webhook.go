@@ -0,0 +1,213 @@
+package agent
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// telegramSecretHeader carries the secret_token given to setWebhook.
+const telegramSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// AnyChat in TelegramWebhook.Chats answers commands from any chat.
+const AnyChat = "*"
+
+// defaultCommandTimeout bounds a command's run when Timeout is unset.
+const defaultCommandTimeout = 5 * time.Minute
+
+// TelegramWebhook receives Telegram updates and answers bot commands in the
+// chat they came from: "/forecast" replies with the wind report and
+// "/forecast 7" with the next 7 days only. Telegram only delivers webhooks to
+// a public HTTPS URL, set with Register or the Bot API's setWebhook. Replies
+// go through the agent's Telegram notifier, so TelegramToken and
+// TelegramChatID must be set.
+type TelegramWebhook struct {
+	Agent *Agent
+	// SecretToken must match the X-Telegram-Bot-Api-Secret-Token header
+	// Telegram sends with each update. An empty token rejects every update.
+	SecretToken string
+	// Chats limits commands to these chat IDs; include AnyChat to answer
+	// every chat. Empty allows only the agent's TelegramChatID.
+	Chats []string
+	// Timeout bounds each command's run. Defaults to 5 minutes.
+	Timeout time.Duration
+
+	// running is held while a command runs.
+	running sync.Mutex
+	// commands tracks the background command runs for Shutdown.
+	commands sync.WaitGroup
+}
+
+// telegramUpdate is the part of a Telegram Update the webhook reads.
+type telegramUpdate struct {
+	UpdateID    int64            `json:"update_id"`
+	Message     *telegramInbound `json:"message"`
+	ChannelPost *telegramInbound `json:"channel_post"`
+}
+
+// telegramInbound is a message sent to the bot.
+type telegramInbound struct {
+	Chat struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+	Text string `json:"text"`
+}
+
+// ServeHTTP implements http.Handler. It acknowledges updates straight away
+// and runs commands in the background, since Telegram resends updates that
+// aren't acknowledged quickly.
+func (h *TelegramWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	got := r.Header.Get(telegramSecretHeader)
+	if h.SecretToken == "" || subtle.ConstantTimeCompare([]byte(got), []byte(h.SecretToken)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var update telegramUpdate
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&update); err != nil {
+		http.Error(w, "bad update", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+
+	msg := update.Message
+	if msg == nil {
+		msg = update.ChannelPost
+	}
+	if msg == nil {
+		return
+	}
+	name, args, ok := parseCommand(msg.Text)
+	if !ok {
+		return
+	}
+	chatID := strconv.FormatInt(msg.Chat.ID, 10)
+	if !h.allows(chatID) {
+		fmt.Printf("warning: ignoring /%s from chat %s, not in the allowed chats\n", name, chatID)
+		return
+	}
+	bot := h.Agent.telegram
+	if bot == nil {
+		fmt.Printf("warning: can't answer /%s: Telegram isn't configured\n", name)
+		return
+	}
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = defaultCommandTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), timeout)
+	h.commands.Add(1)
+	go func() {
+		defer h.commands.Done()
+		defer cancel()
+		h.answer(ctx, bot, chatID, name, args)
+	}()
+}
+
+// Shutdown waits for commands still running to reply, or for ctx to be done.
+// Stop the HTTP server first so no new commands start.
+func (h *TelegramWebhook) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		h.commands.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// allows reports whether commands from chatID are answered.
+func (h *TelegramWebhook) allows(chatID string) bool {
+	if len(h.Chats) == 0 {
+		return h.Agent.telegram != nil && chatID == h.Agent.telegram.ChatID
+	}
+	return slices.Contains(h.Chats, AnyChat) || slices.Contains(h.Chats, chatID)
+}
+
+// answer runs one command and replies in chatID. Unknown commands are
+// ignored, as they may be meant for another bot in the chat.
+func (h *TelegramWebhook) answer(ctx context.Context, bot *TelegramNotifier, chatID, name string, args []string) {
+	a := h.Agent
+	maxDays := a.cfg.WindDays
+	switch name {
+	case "start", "help":
+		h.reply(ctx, bot, chatID, a.tr.T(msgCommandHelp, maxDays))
+	case "forecast":
+		days := maxDays
+		if len(args) > 0 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 || n > maxDays {
+				h.reply(ctx, bot, chatID, a.tr.T(msgCommandDays, maxDays))
+				return
+			}
+			days = n
+		}
+		if !h.running.TryLock() {
+			h.reply(ctx, bot, chatID, a.tr.T(msgCommandBusy))
+			return
+		}
+		defer h.running.Unlock()
+
+		fmt.Printf("🔔 /forecast %d requested over Telegram by chat %s\n", days, chatID)
+		r, err := a.Forecast(ctx, days)
+		if err != nil {
+			fmt.Printf("warning: /forecast: %v\n", err)
+		}
+		if r.Kind == "" {
+			h.reply(ctx, bot, chatID, a.tr.T(msgCommandFailed))
+			return
+		}
+		for _, msg := range bot.messages(r) {
+			if _, err := bot.send(ctx, chatID, msg); err != nil {
+				fmt.Printf("warning: reply to /forecast: %v\n", err)
+				return
+			}
+		}
+	}
+}
+
+// reply sends a short plain message, escaped for the bot's parse mode.
+func (h *TelegramWebhook) reply(ctx context.Context, bot *TelegramNotifier, chatID, text string) {
+	tr := TelegramRenderer{ParseMode: bot.ParseMode}
+	if _, err := bot.send(ctx, chatID, tr.escape(text)); err != nil {
+		fmt.Printf("warning: reply in chat %s: %v\n", chatID, err)
+	}
+}
+
+// parseCommand splits a bot command such as "/forecast@MyBot 7" into its
+// lower-cased name and arguments. ok is false for text that isn't a command.
+func parseCommand(text string) (name string, args []string, ok bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return "", nil, false
+	}
+	name, _, _ = strings.Cut(strings.TrimPrefix(fields[0], "/"), "@")
+	if name == "" {
+		return "", nil, false
+	}
+	return strings.ToLower(name), fields[1:], true
+}
+
+// Register points the bot's webhook at url, a public HTTPS URL routed to
+// this handler, so Telegram starts delivering updates with SecretToken.
+func (h *TelegramWebhook) Register(ctx context.Context, url string) error {
+	bot := h.Agent.telegram
+	if bot == nil {
+		return fmt.Errorf("register telegram webhook: Telegram isn't configured")
+	}
+	return bot.call(ctx, "setWebhook", map[string]any{
+		"url":             url,
+		"secret_token":    h.SecretToken,
+		"allowed_updates": []string{"message", "channel_post"},
+	}, nil)
+}
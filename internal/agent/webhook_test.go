@@ -0,0 +1,98 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookChats(t *testing.T) {
+	tests := []struct {
+		name    string
+		chats   []string
+		allowed []string // of chats 1, 2 and 3, sending in that order
+	}{
+		{"default", nil, []string{"1"}},
+		{"listed", []string{"2", "3"}, []string{"2", "3"}},
+		{"any", []string{AnyChat}, []string{"1", "2", "3"}},
+	}
+	for _, tt := range tests {
+		tg, url := newFakeTelegram(t)
+		a := newTestAgent(t, Config{TelegramToken: "token", TelegramChatID: "1", TelegramBaseURL: url})
+		h := &TelegramWebhook{Agent: a, SecretToken: "secret", Chats: tt.chats}
+		for _, chat := range []string{"1", "2", "3"} {
+			req := httptest.NewRequest(http.MethodPost, "/telegram", strings.NewReader(`{"update_id":1,"message":{"chat":{"id":`+chat+`},"text":"/help"}}`))
+			req.Header.Set(telegramSecretHeader, "secret")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("%s: chat %s: status %d, want 200", tt.name, chat, w.Code)
+			}
+		}
+		// Replies are sent in the background.
+		if err := h.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		tg.mu.Lock()
+		got := slices.Sorted(slices.Values(tg.chats))
+		tg.mu.Unlock()
+		if !slices.Equal(got, tt.allowed) {
+			t.Errorf("%s: replied in chats %v, want %v", tt.name, got, tt.allowed)
+		}
+	}
+}
+
+func TestWebhookSecret(t *testing.T) {
+	tg, url := newFakeTelegram(t)
+	a := newTestAgent(t, Config{TelegramToken: "token", TelegramChatID: "1", TelegramBaseURL: url})
+	for _, h := range []*TelegramWebhook{
+		{Agent: a, SecretToken: "secret"},
+		{Agent: a},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/telegram", strings.NewReader(`{"update_id":1,"message":{"chat":{"id":1},"text":"/help"}}`))
+		req.Header.Set(telegramSecretHeader, "wrong")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("secret %q: status %d, want 401", h.SecretToken, w.Code)
+		}
+		if err := h.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	if len(tg.chats) != 0 {
+		t.Errorf("replied to unauthorized updates in chats %v", tg.chats)
+	}
+}
+
+func TestWebhookShutdown(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	t.Cleanup(srv.Close)
+	a := newTestAgent(t, Config{TelegramToken: "token", TelegramChatID: "1", TelegramBaseURL: srv.URL})
+	h := &TelegramWebhook{Agent: a, SecretToken: "secret"}
+	req := httptest.NewRequest(http.MethodPost, "/telegram", strings.NewReader(`{"update_id":1,"message":{"chat":{"id":1},"text":"/help"}}`))
+	req.Header.Set(telegramSecretHeader, "secret")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	// The reply is stuck, so Shutdown gives up when its context does.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := h.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown with a stuck reply = %v, want %v", err, context.DeadlineExceeded)
+	}
+	close(release)
+	if err := h.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown after the reply = %v, want nil", err)
+	}
+}
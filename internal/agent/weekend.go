@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"math"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// GroupStats aggregates a set of forecast days.
+type GroupStats struct {
+	Days          int
+	AvgWind       float64 // in the forecast's wind speed unit
+	AvgGust       float64
+	EasterlyShare float64 // 0-1
+}
+
+// CompareWeekends splits days into weekend and weekday groups. A group with
+// no days has zero Days and zero averages. minSpeed is as for
+// Config.MinEasterlySpeed.
+func CompareWeekends(days []weather.ForecastDay, minSpeed float64) (weekend, weekday GroupStats) {
+	for _, d := range days {
+		g := &weekday
+		if wd := d.Date.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			g = &weekend
+		}
+		g.Days++
+		g.AvgWind += d.WindSpeedMax
+		g.AvgGust += d.WindGustMax
+		if dayEasterly(d, minSpeed) {
+			g.EasterlyShare++
+		}
+	}
+	for _, g := range []*GroupStats{&weekend, &weekday} {
+		if g.Days > 0 {
+			n := float64(g.Days)
+			g.AvgWind /= n
+			g.AvgGust /= n
+			g.EasterlyShare /= n
+		}
+	}
+	return weekend, weekday
+}
+
+// minWeekendDiff is the average wind difference (km/h) below which weekends
+// and weekdays count as similar, whatever the wind check's unit.
+const minWeekendDiff = 3
+
+// weekendLine compares weekends with weekdays, or returns "" when the window
+// lacks one of them.
+func (a *Agent) weekendLine(days []weather.ForecastDay) string {
+	we, wd := CompareWeekends(days, a.cfg.MinEasterlySpeed)
+	if we.Days == 0 || wd.Days == 0 {
+		return ""
+	}
+	key := msgWeekendsSimilar
+	switch diff := a.kmh(we.AvgWind - wd.AvgWind); {
+	case diff <= -minWeekendDiff:
+		key = msgWeekendsCalmer
+	case diff >= minWeekendDiff:
+		key = msgWeekendsWindier
+	}
+	return a.tr.T(key, we.AvgWind, wd.AvgWind, a.windSymbol(),
+		int(math.Round(we.EasterlyShare*100)), int(math.Round(wd.EasterlyShare*100)))
+}
@@ -0,0 +1,106 @@
+package agent
+
+import (
+	"strings"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+// WeekBucket aggregates the forecast days falling in one week. Buckets at
+// the start or end of the window may hold fewer than seven days.
+type WeekBucket struct {
+	// Start is the week's first day, which may precede the first forecast day.
+	Start time.Time
+	// Year and Week are the ISO week containing Start.
+	Year     int
+	Week     int
+	Days     []weather.ForecastDay
+	MaxGust  float64
+	Easterly int
+	// Dominant is "E", "W" or "Mixed" based on easterly vs westerly day counts.
+	Dominant string
+}
+
+// WeeklySummary groups days into weeks beginning on weekStart (time.Monday
+// gives ISO weeks), preserving their order.
+func WeeklySummary(days []weather.ForecastDay, minSpeed float64, weekStart time.Weekday) []WeekBucket {
+	var out []WeekBucket
+	for _, d := range days {
+		start := weekStartOf(d.Date, weekStart)
+		if len(out) == 0 || !sameDay(out[len(out)-1].Start, start) {
+			year, week := start.ISOWeek()
+			out = append(out, WeekBucket{Start: start, Year: year, Week: week})
+		}
+		b := &out[len(out)-1]
+		b.Days = append(b.Days, d)
+		b.MaxGust = max(b.MaxGust, d.WindGustMax)
+		if dayEasterly(d, minSpeed) {
+			b.Easterly++
+		}
+	}
+	for i := range out {
+		b := &out[i]
+		west := len(b.Days) - b.Easterly - countLightEasterly(b.Days, minSpeed) - countUnknownDirection(b.Days)
+		switch {
+		case b.Easterly > west:
+			b.Dominant = "E"
+		case west > b.Easterly:
+			b.Dominant = "W"
+		default:
+			b.Dominant = "Mixed"
+		}
+	}
+	return out
+}
+
+// weekStartOf returns the date of the most recent weekStart on or before t.
+func weekStartOf(t time.Time, weekStart time.Weekday) time.Time {
+	back := (int(t.Weekday()) - int(weekStart) + 7) % 7
+	y, m, d := t.AddDate(0, 0, -back).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// parseWeekday reads an English weekday name or its three-letter
+// abbreviation, case-insensitively.
+func parseWeekday(s string) (time.Weekday, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// formatWeeklySummary renders one terse line per week, e.g.
+// "Week 1: mostly W, gusts to 40; easterly Tue–Thu".
+func formatWeeklySummary(buckets []WeekBucket, minSpeed float64, tr translator) string {
+	var b strings.Builder
+	for i, w := range buckets {
+		dominant := tr.T(msgMixed)
+		switch w.Dominant {
+		case "E":
+			dominant = tr.T(msgEast)
+		case "W":
+			dominant = tr.T(msgWest)
+		}
+		b.WriteString(tr.T(msgWeekLine, i+1, dominant, w.MaxGust))
+		if w.Easterly > 0 {
+			b.WriteString(tr.T(msgWeekEasterly, easterlyDayRanges(w.Days, minSpeed, tr)))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// easterlyDayRanges lists the easterly weekdays, collapsing consecutive days
+// into ranges ("Tue–Thu, Sat").
+func easterlyDayRanges(days []weather.ForecastDay, minSpeed float64, tr translator) string {
+	var parts []string
+	for _, s := range EasterlyStreaks(days, minSpeed) {
+		parts = append(parts, streakRange(s, tr))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,44 @@
+package agent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeeklySummary(t *testing.T) {
+	type bucket struct {
+		start          time.Time
+		days, easterly int
+		maxGust        float64
+		dominant       string
+	}
+	jan := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		weekStart time.Weekday
+		want      []bucket
+	}{
+		{time.Monday, []bucket{
+			{jan(6), 7, 4, 130, "E"},
+			{jan(13), 1, 1, 44, "E"},
+		}},
+		// Sunday 5 January precedes the first forecast day.
+		{time.Sunday, []bucket{
+			{jan(5), 6, 3, 130, "Mixed"},
+			{jan(12), 2, 2, 44, "E"},
+		}},
+	}
+	for _, tt := range tests {
+		got := WeeklySummary(sampleForecast(), 0, tt.weekStart)
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s: %d weeks, want %d", tt.weekStart, len(got), len(tt.want))
+		}
+		for i, w := range tt.want {
+			g := got[i]
+			if !g.Start.Equal(w.start) || len(g.Days) != w.days || g.Easterly != w.easterly || g.MaxGust != w.maxGust || g.Dominant != w.dominant {
+				t.Errorf("%s week %d: start %s, %d days, %d easterly, gusts %v, %s; want %s, %d, %d, %v, %s",
+					tt.weekStart, i, g.Start.Format("Mon 02"), len(g.Days), g.Easterly, g.MaxGust, g.Dominant,
+					w.start.Format("Mon 02"), w.days, w.easterly, w.maxGust, w.dominant)
+			}
+		}
+	}
+}
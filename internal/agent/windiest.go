@@ -0,0 +1,49 @@
+package agent
+
+import "github.com/emanuelefumagalli/test-agent/internal/weather"
+
+// WindiestDay returns the day with the highest gusts, the earliest on a tie,
+// and false when days is empty.
+func WindiestDay(days []weather.ForecastDay) (weather.ForecastDay, bool) {
+	if len(days) == 0 {
+		return weather.ForecastDay{}, false
+	}
+	best := days[0]
+	for _, d := range days[1:] {
+		if d.WindGustMax > best.WindGustMax || (d.WindGustMax == best.WindGustMax && d.Date.Before(best.Date)) {
+			best = d
+		}
+	}
+	return best, true
+}
+
+// CalmestDay returns the day with the lowest max wind speed, the earliest on
+// a tie, and false when days is empty.
+func CalmestDay(days []weather.ForecastDay) (weather.ForecastDay, bool) {
+	if len(days) == 0 {
+		return weather.ForecastDay{}, false
+	}
+	best := days[0]
+	for _, d := range days[1:] {
+		if d.WindSpeedMax < best.WindSpeedMax || (d.WindSpeedMax == best.WindSpeedMax && d.Date.Before(best.Date)) {
+			best = d
+		}
+	}
+	return best, true
+}
+
+// windiestLine reports the windiest day, e.g. "Windiest: Thu 16 Jan (gusts
+// 58 km/h ⚠)", marked when the gusts reach the gust marker or alert
+// threshold. It returns "" for no days.
+func (a *Agent) windiestLine(days []weather.ForecastDay) string {
+	d, ok := WindiestDay(days)
+	if !ok {
+		return ""
+	}
+	line := a.tr.T(msgWindiest, a.tr.Day(d.Date), d.WindGustMax, a.windSymbol())
+	if (a.cfg.GustMarkerThreshold > 0 && d.WindGustMax >= a.cfg.GustMarkerThreshold) ||
+		(a.cfg.GustAlertThreshold > 0 && d.WindGustMax >= a.cfg.GustAlertThreshold) {
+		line += " " + a.cfg.Theme.Gust
+	}
+	return line
+}
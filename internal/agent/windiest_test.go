@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/weather"
+)
+
+func TestWindiestDay(t *testing.T) {
+	if _, ok := WindiestDay(nil); ok {
+		t.Error("WindiestDay(nil) ok, want false")
+	}
+
+	day := func(d int, gust float64) weather.ForecastDay {
+		return weather.ForecastDay{Date: time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC), WindGustMax: gust}
+	}
+	tests := []struct {
+		name string
+		days []weather.ForecastDay
+		want int // day of January
+	}{
+		{"single", []weather.ForecastDay{day(6, 30)}, 6},
+		{"strongest gusts", []weather.ForecastDay{day(6, 30), day(7, 58), day(8, 41)}, 7},
+		{"tie goes to the earliest", []weather.ForecastDay{day(6, 20), day(8, 58), day(7, 58)}, 7},
+	}
+	for _, tt := range tests {
+		got, ok := WindiestDay(tt.days)
+		if !ok || got.Date.Day() != tt.want {
+			t.Errorf("%s: WindiestDay = %d Jan (ok %v), want %d Jan", tt.name, got.Date.Day(), ok, tt.want)
+		}
+	}
+}
+
+func TestCalmestDay(t *testing.T) {
+	if _, ok := CalmestDay(nil); ok {
+		t.Error("CalmestDay(nil) ok, want false")
+	}
+	day := func(d int, speed float64) weather.ForecastDay {
+		return weather.ForecastDay{Date: time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC), WindSpeedMax: speed}
+	}
+	got, ok := CalmestDay([]weather.ForecastDay{day(6, 20), day(8, 9), day(7, 9), day(9, 30)})
+	if !ok || got.Date.Day() != 7 {
+		t.Errorf("CalmestDay = %d Jan (ok %v), want the earlier of the calmest, 7 Jan", got.Date.Day(), ok)
+	}
+}
+
+func TestWindiestLine(t *testing.T) {
+	days := []weather.ForecastDay{{Date: time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC), WindGustMax: 58}}
+	tests := []struct {
+		cfg  Config
+		want string
+	}{
+		{Config{}, "Windiest: Thu 09 Jan (gusts 58 km/h)"},
+		{Config{GustMarkerThreshold: 58}, "Windiest: Thu 09 Jan (gusts 58 km/h) ⚠"},
+		{Config{GustAlertThreshold: 50}, "Windiest: Thu 09 Jan (gusts 58 km/h) ⚠"},
+		{Config{GustAlertThreshold: 60}, "Windiest: Thu 09 Jan (gusts 58 km/h)"},
+	}
+	for _, tt := range tests {
+		a := newTestAgent(t, tt.cfg)
+		if got := a.windiestLine(days); got != tt.want {
+			t.Errorf("windiestLine = %q, want %q", got, tt.want)
+		}
+	}
+	if got := newTestAgent(t, Config{}).windiestLine(nil); got != "" {
+		t.Errorf("windiestLine(nil) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,84 @@
+// Package httpx provides the shared outbound HTTP plumbing: a single
+// proxy-aware transport and a User-Agent on every request.
+package httpx
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultUserAgent identifies the agent to public APIs.
+const DefaultUserAgent = "personal-weather-agent/1.0"
+
+// sharedTransport is reused by every client so connections are pooled. It
+// honours HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+var sharedTransport = func() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = http.ProxyFromEnvironment
+	return t
+}()
+
+// userAgentTransport sets User-Agent on requests that don't carry one.
+type userAgentTransport struct {
+	base      http.RoundTripper
+	userAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// NewClient returns a client on the shared transport that sends userAgent
+// (DefaultUserAgent when empty). A zero timeout means no client timeout.
+func NewClient(timeout time.Duration, userAgent string) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: WithUserAgent(sharedTransport, userAgent),
+	}
+}
+
+// NewProxyClient is NewClient on a transport of its own that sends every
+// request through proxy, ignoring the *_PROXY environment. proxy may be
+// http, https, socks5 or socks5h (remote DNS), with optional user:password;
+// net/http dials SOCKS itself, within the request's context and timeout.
+func NewProxyClient(timeout time.Duration, userAgent string, proxy *url.URL) *http.Client {
+	t := sharedTransport.Clone()
+	t.Proxy = http.ProxyURL(proxy)
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: WithUserAgent(t, userAgent),
+	}
+}
+
+// ParseProxyURL parses a proxy URL for NewProxyClient, rejecting schemes
+// net/http can't use.
+func ParseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse proxy URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (want http, https, socks5 or socks5h)", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", raw)
+	}
+	return u, nil
+}
+
+// WithUserAgent wraps base so requests without a User-Agent get userAgent
+// (DefaultUserAgent when empty).
+func WithUserAgent(base http.RoundTripper, userAgent string) http.RoundTripper {
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	return &userAgentTransport{base: base, userAgent: userAgent}
+}
@@ -6,9 +6,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/httpx"
 )
 
 // Client talks to a local Ollama instance (https://ollama.com/).
@@ -16,18 +22,70 @@ type Client struct {
 	Host       string
 	Model      string
 	HTTPClient *http.Client
+	// KeepAlive controls how long Ollama keeps the model loaded after the
+	// request (e.g. "24h", or "-1" for indefinitely). Empty uses Ollama's default.
+	KeepAlive string
+	// UserAgent is sent on every request. Defaults to httpx.DefaultUserAgent.
+	UserAgent string
+	// Timeout bounds a whole Generate call, including reading the response.
+	// Defaults to 15 minutes, enough for a cold model load on modest hardware.
+	// Time spent waiting for a MaxConcurrent slot doesn't count.
+	Timeout time.Duration
+	// MaxConcurrent caps the Generate calls in flight at once on this
+	// client; the rest wait their turn. Defaults to 1, as one local model
+	// on a small GPU times out when asked for several summaries at once.
+	MaxConcurrent int
+
+	semOnce sync.Once
+	sem     chan struct{}
 }
 
+const defaultTimeout = 15 * time.Minute
+
+// Generate's failures fall in three groups: ErrUnavailable when the server
+// can't be reached at all, ErrModelNotFound when it doesn't have the model,
+// and any other error for a failed generation. Test with errors.Is.
+var (
+	ErrUnavailable   = errors.New("ollama unavailable")
+	ErrModelNotFound = errors.New("ollama model not found")
+)
+
+// BaseURL returns the Ollama server the client calls: Host, or the local
+// default.
+func (c *Client) BaseURL() string {
+	if c.Host == "" {
+		return "http://127.0.0.1:11434"
+	}
+	return c.Host
+}
+
+// acquire takes a MaxConcurrent slot, waiting until one frees up or ctx ends.
+func (c *Client) acquire(ctx context.Context) error {
+	c.semOnce.Do(func() {
+		c.sem = make(chan struct{}, max(c.MaxConcurrent, 1))
+	})
+	select {
+	case c.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Client) release() { <-c.sem }
+
 // Generate sends a prompt to Ollama and returns the model response (non-streaming).
 func (c *Client) Generate(ctx context.Context, prompt string) (string, error) {
 	if strings.TrimSpace(prompt) == "" {
 		return "", errors.New("prompt cannot be empty")
 	}
 
-	host := c.Host
-	if host == "" {
-		host = "http://127.0.0.1:11434"
+	if err := c.acquire(ctx); err != nil {
+		return "", fmt.Errorf("wait for ollama: %w", err)
 	}
+	defer c.release()
+
+	host := c.BaseURL()
 
 	model := c.Model
 	if model == "" {
@@ -39,27 +97,47 @@ func (c *Client) Generate(ctx context.Context, prompt string) (string, error) {
 		"prompt": prompt,
 		"stream": false,
 	}
+	if c.KeepAlive != "" {
+		payload["keep_alive"] = keepAliveValue(c.KeepAlive)
+	}
 
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return "", fmt.Errorf("marshal ollama payload: %w", err)
 	}
 
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, host+"/api/generate", bytes.NewReader(body))
 	if err != nil {
 		return "", fmt.Errorf("build ollama request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
 
 	client := c.HTTPClient
 	if client == nil {
-		client = &http.Client{
-			Timeout: 15 * time.Minute,
-		}
+		client = httpx.NewClient(0, c.UserAgent)
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("call ollama: %w", ctx.Err())
+		}
+		// Dial failures (refused, no route, unknown host) mean the server
+		// isn't there, as opposed to one that failed mid-request.
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return "", fmt.Errorf("call ollama: %w at %s: %w", ErrUnavailable, host, err)
+		}
 		return "", fmt.Errorf("call ollama: %w", err)
 	}
 	defer func() {
@@ -68,16 +146,60 @@ func (c *Client) Generate(ctx context.Context, prompt string) (string, error) {
 		}
 	}()
 
+	if resp.StatusCode == http.StatusNotFound {
+		// Ollama answers a model it hasn't pulled with 404.
+		return "", fmt.Errorf("%w: %q (ollama pull %s)", ErrModelNotFound, model, model)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("ollama returned %s", resp.Status)
 	}
 
-	var result struct {
-		Response string `json:"response"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	text, err := decodeResponse(resp.Body)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("read ollama response: %w", ctx.Err())
+		}
 		return "", fmt.Errorf("decode ollama response: %w", err)
 	}
 
-	return strings.TrimSpace(result.Response), nil
+	return strings.TrimSpace(text), nil
+}
+
+// decodeResponse reads a generate response. Some Ollama versions and proxies
+// stream NDJSON even when asked not to, so every object in the body is read
+// and their response fields concatenated; a single object is the common case.
+// Anything unparseable after the first object is ignored with a warning.
+func decodeResponse(r io.Reader) (string, error) {
+	dec := json.NewDecoder(r)
+	var text strings.Builder
+	for n := 0; ; n++ {
+		var chunk struct {
+			Response string `json:"response"`
+			Error    string `json:"error"`
+		}
+		err := dec.Decode(&chunk)
+		if errors.Is(err, io.EOF) && n > 0 {
+			return text.String(), nil
+		}
+		if err != nil {
+			if n > 0 {
+				fmt.Printf("warning: ignoring trailing ollama output: %v\n", err)
+				return text.String(), nil
+			}
+			return "", err
+		}
+		if chunk.Error != "" {
+			return "", fmt.Errorf("ollama error: %s", chunk.Error)
+		}
+		text.WriteString(chunk.Response)
+	}
+}
+
+// keepAliveValue passes plain integers (e.g. "-1") as numbers, which Ollama
+// interprets as seconds, and anything else as a duration string.
+func keepAliveValue(v string) any {
+	if n, err := strconv.Atoi(v); err == nil {
+		return n
+	}
+	return v
 }
@@ -0,0 +1,124 @@
+package ollama
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowServer answers after delay, or when the request is abandoned.
+func slowServer(t *testing.T, delay time.Duration) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Reading the body lets the server notice the client hanging up.
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-time.After(delay):
+			_, _ = w.Write([]byte(`{"response":"late"}`))
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGenerateTimeout(t *testing.T) {
+	c := &Client{Host: slowServer(t, 5*time.Second).URL, Timeout: 50 * time.Millisecond}
+	start := time.Now()
+	_, err := c.Generate(context.Background(), "hi")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Generate error = %v, want a deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Generate took %s, want it bounded by the 50ms timeout", elapsed)
+	}
+}
+
+func TestGenerateCancel(t *testing.T) {
+	c := &Client{Host: slowServer(t, 5*time.Second).URL}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := c.Generate(ctx, "hi")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Generate error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Generate took %s after cancel, want it to return promptly", elapsed)
+	}
+}
+
+func TestDecodeResponse(t *testing.T) {
+	tests := []struct {
+		name, body, want string
+		wantErr          bool
+	}{
+		{"single object", `{"response":"Easterly all week.","done":true}`, "Easterly all week.", false},
+		{"ndjson", "{\"response\":\"Easterly \"}\n{\"response\":\"all week.\"}\n{\"response\":\"\",\"done\":true}\n", "Easterly all week.", false},
+		{"trailing garbage", `{"response":"Easterly."}` + "\n<html>", "Easterly.", false},
+		{"error object", `{"error":"model is loading"}`, "", true},
+		{"not json", "<html>", "", true},
+		{"empty", "", "", true},
+	}
+	for _, tt := range tests {
+		got, err := decodeResponse(strings.NewReader(tt.body))
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s: decodeResponse = %q, %v; want %q, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestGenerateNDJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = w.Write([]byte("{\"response\":\" Mostly\"}\n{\"response\":\" easterly. \",\"done\":true}\n"))
+	}))
+	t.Cleanup(srv.Close)
+	got, err := (&Client{Host: srv.URL}).Generate(context.Background(), "hi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Mostly easterly." {
+		t.Errorf("Generate = %q, want the chunks joined and trimmed", got)
+	}
+}
+
+func TestGenerateErrorKinds(t *testing.T) {
+	status := func(code int) string {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(code), code)
+		}))
+		t.Cleanup(srv.Close)
+		return srv.URL
+	}
+	// A closed server's address refuses the dial.
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		name, host                 string
+		unavailable, modelNotFound bool
+	}{
+		{"refused", closed.URL, true, false},
+		{"missing model", status(http.StatusNotFound), false, true},
+		{"server error", status(http.StatusInternalServerError), false, false},
+	}
+	for _, tt := range tests {
+		_, err := (&Client{Host: tt.host}).Generate(context.Background(), "hi")
+		if err == nil {
+			t.Errorf("%s: want an error", tt.name)
+			continue
+		}
+		if got := errors.Is(err, ErrUnavailable); got != tt.unavailable {
+			t.Errorf("%s: errors.Is(%v, ErrUnavailable) = %v, want %v", tt.name, err, got, tt.unavailable)
+		}
+		if got := errors.Is(err, ErrModelNotFound); got != tt.modelNotFound {
+			t.Errorf("%s: errors.Is(%v, ErrModelNotFound) = %v, want %v", tt.name, err, got, tt.modelNotFound)
+		}
+	}
+}
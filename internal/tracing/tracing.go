@@ -0,0 +1,74 @@
+// Package tracing wires the agent's OpenTelemetry spans: a TracerProvider
+// exporting over OTLP/HTTP when the standard OTEL_* variables name a
+// collector, and helpers that trace only within an already traced run.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Name is the instrumentation scope of the agent's spans.
+const Name = "github.com/emanuelefumagalli/test-agent"
+
+// defaultServiceName is reported unless OTEL_SERVICE_NAME overrides it.
+const defaultServiceName = "personal-weather-agent"
+
+// Configured reports whether OTEL_EXPORTER_OTLP_ENDPOINT or
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT names a collector.
+func Configured() bool {
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// NewProvider returns a TracerProvider that batches spans to the collector
+// named by the OTEL_EXPORTER_OTLP_* variables, sending them with client.
+// Shut it down to flush the last spans.
+func NewProvider(ctx context.Context, client *http.Client) (*sdktrace.TracerProvider, error) {
+	exp, err := otlptracehttp.New(ctx, otlptracehttp.WithHTTPClient(client))
+	if err != nil {
+		return nil, err
+	}
+	// Later detectors win, so OTEL_SERVICE_NAME replaces the default.
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", defaultServiceName)),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return sdktrace.NewTracerProvider(sdktrace.WithBatcher(exp), sdktrace.WithResource(res)), nil
+}
+
+// Tracer returns the agent's tracer from tp, or a no-op tracer when tp is nil.
+func Tracer(tp trace.TracerProvider) trace.Tracer {
+	if tp == nil {
+		tp = noop.NewTracerProvider()
+	}
+	return tp.Tracer(Name)
+}
+
+// Start begins a child of the span in ctx using that span's provider. When
+// ctx has no span the child is a no-op, so instrumented helpers trace only
+// within a traced run.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	parent := trace.SpanFromContext(ctx)
+	return parent.TracerProvider().Tracer(Name).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End finishes span, recording err and an error status when err is non-nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
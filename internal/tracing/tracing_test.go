@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartOutsideRun(t *testing.T) {
+	_, span := Start(context.Background(), "fetch")
+	if span.IsRecording() {
+		t.Error("span outside a traced run is recording, want a no-op")
+	}
+	End(span, errors.New("boom"))
+}
+
+func TestStartAndEnd(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	ctx, root := Tracer(tp).Start(context.Background(), "run")
+	_, child := Start(ctx, "fetch")
+	End(child, errors.New("boom"))
+	End(root, nil)
+
+	ended := rec.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("%d spans ended, want 2", len(ended))
+	}
+	fetch, run := ended[0], ended[1]
+	if fetch.Parent().SpanID() != run.SpanContext().SpanID() {
+		t.Error("fetch is not a child of run")
+	}
+	if fetch.Status().Code != codes.Error || fetch.Status().Description != "boom" {
+		t.Errorf("fetch status %+v, want Error boom", fetch.Status())
+	}
+	if len(fetch.Events()) != 1 || fetch.Events()[0].Name != "exception" {
+		t.Errorf("fetch events %v, want the recorded error", fetch.Events())
+	}
+	if run.Status().Code != codes.Unset {
+		t.Errorf("run status %+v, want Unset", run.Status())
+	}
+}
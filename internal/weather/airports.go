@@ -0,0 +1,53 @@
+package weather
+
+import (
+	"slices"
+	"strings"
+)
+
+type airport struct {
+	iata, icao string
+	name       string
+	lat, lon   float64
+}
+
+// airports covers the London airports and the UK's other major ones.
+var airports = []airport{
+	{"LHR", "EGLL", "London Heathrow", 51.4700, -0.4543},
+	{"LGW", "EGKK", "London Gatwick", 51.1537, -0.1821},
+	{"STN", "EGSS", "London Stansted", 51.8860, 0.2389},
+	{"LTN", "EGGW", "London Luton", 51.8747, -0.3683},
+	{"LCY", "EGLC", "London City", 51.5048, 0.0495},
+	{"SEN", "EGMC", "London Southend", 51.5714, 0.6956},
+	{"MAN", "EGCC", "Manchester", 53.3537, -2.2750},
+	{"BHX", "EGBB", "Birmingham", 52.4539, -1.7480},
+	{"BRS", "EGGD", "Bristol", 51.3827, -2.7191},
+	{"EMA", "EGNX", "East Midlands", 52.8311, -1.3281},
+	{"LPL", "EGGP", "Liverpool", 53.3336, -2.8497},
+	{"NCL", "EGNT", "Newcastle", 55.0375, -1.6917},
+	{"EDI", "EGPH", "Edinburgh", 55.9500, -3.3725},
+	{"GLA", "EGPF", "Glasgow", 55.8719, -4.4331},
+	{"BFS", "EGAA", "Belfast International", 54.6575, -6.2158},
+}
+
+// LookupAirport resolves an IATA (LHR) or ICAO (EGLL) code, case-insensitively,
+// to the airport's coordinates and name.
+func LookupAirport(code string) (lat, lon float64, name string, ok bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	for _, a := range airports {
+		if a.iata == code || a.icao == code {
+			return a.lat, a.lon, a.name, true
+		}
+	}
+	return 0, 0, "", false
+}
+
+// AirportCodes lists the IATA codes LookupAirport knows, sorted.
+func AirportCodes() []string {
+	codes := make([]string, len(airports))
+	for i, a := range airports {
+		codes[i] = a.iata
+	}
+	slices.Sort(codes)
+	return codes
+}
@@ -0,0 +1,15 @@
+package weather
+
+import "testing"
+
+func TestLookupAirport(t *testing.T) {
+	for _, code := range []string{"LHR", "egll", " lhr "} {
+		lat, lon, name, ok := LookupAirport(code)
+		if !ok || name != "London Heathrow" || lat != 51.47 || lon != -0.4543 {
+			t.Errorf("LookupAirport(%q) = %v, %v, %q, %v; want Heathrow", code, lat, lon, name, ok)
+		}
+	}
+	if _, _, _, ok := LookupAirport("JFK"); ok {
+		t.Error("LookupAirport(JFK) found an airport outside the table")
+	}
+}
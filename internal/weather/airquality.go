@@ -0,0 +1,171 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	airQualityBaseURL         = "https://air-quality-api.open-meteo.com/v1/air-quality"
+	airQualityCustomerBaseURL = "https://customer-air-quality-api.open-meteo.com/v1/air-quality"
+)
+
+// AirQualityClient fetches forecasts from Open-Meteo's air-quality API.
+type AirQualityClient struct {
+	Latitude   float64
+	Longitude  float64
+	HTTPClient *http.Client
+	// Timeout bounds each request, see OpenMeteoClient.Timeout.
+	Timeout time.Duration
+	// UserAgent is sent on every request. Defaults to httpx.DefaultUserAgent.
+	UserAgent string
+	// BaseURL overrides the air-quality endpoint for tests and self-hosted
+	// instances.
+	BaseURL string
+	// APIKey authenticates against the commercial tier, see
+	// OpenMeteoClient.APIKey.
+	APIKey string
+	// Limiter paces requests, see OpenMeteoClient.Limiter.
+	Limiter *RateLimiter
+	// Debug receives raw response bodies, see OpenMeteoClient.Debug.
+	Debug io.Writer
+}
+
+// AirQualityDay holds the daily maxima of the hourly air-quality forecast.
+// Pollen is only forecast for Europe and a few days ahead; HasPollen is false
+// when there was none for the day.
+type AirQualityDay struct {
+	Date        time.Time
+	EuropeanAQI int
+	PM25        float64 // μg/m³
+	PM10        float64 // μg/m³
+	GrassPollen float64 // grains/m³
+	TreePollen  float64 // grains/m³, alder + birch + olive
+	HasPollen   bool
+}
+
+// AQICategory names the European AQI band for aqi.
+func AQICategory(aqi int) string {
+	switch {
+	case aqi <= 20:
+		return "good"
+	case aqi <= 40:
+		return "fair"
+	case aqi <= 60:
+		return "moderate"
+	case aqi <= 80:
+		return "poor"
+	case aqi <= 100:
+		return "very poor"
+	default:
+		return "extremely poor"
+	}
+}
+
+type airQualityResponse struct {
+	Hourly *struct {
+		Time        []string   `json:"time"`
+		EuropeanAQI []*float64 `json:"european_aqi"`
+		PM25        []*float64 `json:"pm2_5"`
+		PM10        []*float64 `json:"pm10"`
+		GrassPollen []*float64 `json:"grass_pollen"`
+		AlderPollen []*float64 `json:"alder_pollen"`
+		BirchPollen []*float64 `json:"birch_pollen"`
+		OlivePollen []*float64 `json:"olive_pollen"`
+	} `json:"hourly"`
+}
+
+// FetchAirQuality retrieves daily AQI, particulates and pollen for the next
+// days (Open-Meteo serves at most 7).
+func (c *AirQualityClient) FetchAirQuality(ctx context.Context, days int) ([]AirQualityDay, error) {
+	if days <= 0 {
+		days = 5
+	}
+
+	query := url.Values{}
+	query.Set("hourly", "european_aqi,pm2_5,pm10,grass_pollen,alder_pollen,birch_pollen,olive_pollen")
+	query.Set("forecast_days", fmt.Sprintf("%d", days))
+	query.Set("timezone", "auto")
+
+	baseURL := c.BaseURL
+	switch {
+	case baseURL != "":
+	case c.APIKey != "":
+		baseURL = airQualityCustomerBaseURL
+	default:
+		baseURL = airQualityBaseURL
+	}
+	om := OpenMeteoClient{
+		Latitude:   c.Latitude,
+		Longitude:  c.Longitude,
+		HTTPClient: c.HTTPClient,
+		Timeout:    c.Timeout,
+		UserAgent:  c.UserAgent,
+		BaseURL:    baseURL,
+		APIKey:     c.APIKey,
+		Limiter:    c.Limiter,
+		Debug:      c.Debug,
+	}
+	var payload airQualityResponse
+	if err := om.get(ctx, query, &payload); err != nil {
+		return nil, err
+	}
+	if payload.Hourly == nil {
+		return nil, decodeError(errors.New("air-quality response missing hourly block"))
+	}
+	h := payload.Hourly
+	n := len(h.Time)
+	for _, arr := range [][]*float64{h.EuropeanAQI, h.PM25, h.PM10, h.GrassPollen, h.AlderPollen, h.BirchPollen, h.OlivePollen} {
+		if len(arr) != n {
+			return nil, decodeError(fmt.Errorf("air-quality hourly arrays mismatch: %d times", n))
+		}
+	}
+
+	var result []AirQualityDay
+	for i, ts := range h.Time {
+		t, err := time.Parse("2006-01-02T15:04", ts)
+		if err != nil {
+			return nil, decodeError(fmt.Errorf("parse time %q: %w", ts, err))
+		}
+		date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		if len(result) == 0 || !result[len(result)-1].Date.Equal(date) {
+			result = append(result, AirQualityDay{Date: date})
+		}
+		d := &result[len(result)-1]
+		if v := h.EuropeanAQI[i]; v != nil && int(*v) > d.EuropeanAQI {
+			d.EuropeanAQI = int(*v)
+		}
+		d.PM25 = maxPresent(d.PM25, h.PM25[i])
+		d.PM10 = maxPresent(d.PM10, h.PM10[i])
+		if v := h.GrassPollen[i]; v != nil {
+			d.HasPollen = true
+			d.GrassPollen = max(d.GrassPollen, round1(*v))
+		}
+		var tree float64
+		var treeSeen bool
+		for _, v := range []*float64{h.AlderPollen[i], h.BirchPollen[i], h.OlivePollen[i]} {
+			if v != nil {
+				tree += *v
+				treeSeen = true
+			}
+		}
+		if treeSeen {
+			d.HasPollen = true
+			d.TreePollen = max(d.TreePollen, round1(tree))
+		}
+	}
+	return result, nil
+}
+
+// maxPresent returns the larger of cur and *v, ignoring a null v.
+func maxPresent(cur float64, v *float64) float64 {
+	if v == nil {
+		return cur
+	}
+	return max(cur, round1(*v))
+}
@@ -0,0 +1,94 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// CurrentConditions is Open-Meteo's "right now" snapshot for a location.
+type CurrentConditions struct {
+	Time          time.Time
+	Temperature   float64 // in the client's TemperatureUnit
+	WindSpeed     float64 // in the client's WindSpeedUnit
+	WindDirection float64 // degrees, 0 = North
+	WeatherCode   int     // WMO weather interpretation code
+}
+
+type currentResponse struct {
+	Current *struct {
+		Time          string  `json:"time"`
+		Temperature   float64 `json:"temperature_2m"`
+		WindSpeed     float64 `json:"wind_speed_10m"`
+		WindDirection float64 `json:"wind_direction_10m"`
+		WeatherCode   int     `json:"weather_code"`
+	} `json:"current"`
+}
+
+// FetchCurrent retrieves the current temperature, wind and weather code.
+func (c *OpenMeteoClient) FetchCurrent(ctx context.Context) (CurrentConditions, error) {
+	tempUnit, err := c.temperatureUnit()
+	if err != nil {
+		return CurrentConditions{}, validationError(err)
+	}
+	windUnit, err := c.windSpeedUnit()
+	if err != nil {
+		return CurrentConditions{}, err
+	}
+
+	query := url.Values{}
+	query.Set("temperature_unit", tempUnit)
+	query.Set("wind_speed_unit", windUnit)
+	query.Set("current", "temperature_2m,wind_speed_10m,wind_direction_10m,weather_code")
+	query.Set("timezone", "auto")
+
+	var payload currentResponse
+	if err := c.get(ctx, query, &payload); err != nil {
+		return CurrentConditions{}, err
+	}
+	if payload.Current == nil {
+		return CurrentConditions{}, decodeError(errors.New("open-meteo response missing current block"))
+	}
+
+	cur := payload.Current
+	t, err := time.Parse("2006-01-02T15:04", cur.Time)
+	if err != nil {
+		return CurrentConditions{}, decodeError(fmt.Errorf("parse current time %q: %w", cur.Time, err))
+	}
+	return CurrentConditions{
+		Time:          t,
+		Temperature:   round1(cur.Temperature),
+		WindSpeed:     round1(cur.WindSpeed),
+		WindDirection: cur.WindDirection,
+		WeatherCode:   cur.WeatherCode,
+	}, nil
+}
+
+// weatherCodes describes the WMO weather interpretation codes Open-Meteo uses.
+var weatherCodes = map[int]string{
+	0: "clear sky", 1: "mainly clear", 2: "partly cloudy", 3: "overcast",
+	45: "fog", 48: "rime fog",
+	51: "light drizzle", 53: "drizzle", 55: "dense drizzle",
+	56: "freezing drizzle", 57: "dense freezing drizzle",
+	61: "light rain", 63: "rain", 65: "heavy rain",
+	66: "freezing rain", 67: "heavy freezing rain",
+	71: "light snow", 73: "snow", 75: "heavy snow", 77: "snow grains",
+	80: "light showers", 81: "showers", 82: "violent showers",
+	85: "snow showers", 86: "heavy snow showers",
+	95: "thunderstorm", 96: "thunderstorm with hail", 99: "thunderstorm with heavy hail",
+}
+
+// WeatherCodeDescription returns an English description of a WMO code.
+func WeatherCodeDescription(code int) string {
+	if d, ok := weatherCodes[code]; ok {
+		return d
+	}
+	return fmt.Sprintf("code %d", code)
+}
+
+// IsThunderstorm reports whether a WMO code denotes a thunderstorm.
+func IsThunderstorm(code int) bool {
+	return code == 95 || code == 96 || code == 99
+}
@@ -0,0 +1,64 @@
+package weather
+
+import (
+	"math"
+	"time"
+)
+
+// DayDelta is how one day's forecast changed between two runs.
+type DayDelta struct {
+	Date       time.Time
+	WindChange float64 // in WindSpeedUnit, current minus previous max wind
+	GustChange float64 // in WindSpeedUnit, current minus previous max gust
+	PrevDir    float64 // degrees
+	CurrDir    float64 // degrees
+	// DirChange is the signed shortest turn from PrevDir to CurrDir, in
+	// (-180, 180].
+	DirChange float64
+	// DirUnknown is set when either forecast lacked a direction; the
+	// direction fields are then zero.
+	DirUnknown bool
+}
+
+// DiffForecasts compares the days present in both forecasts, in curr's order.
+// Days only in one of them are skipped.
+func DiffForecasts(prev, curr []ForecastDay) []DayDelta {
+	byDate := make(map[string]ForecastDay, len(prev))
+	for _, d := range prev {
+		byDate[d.Date.Format("2006-01-02")] = d
+	}
+
+	var out []DayDelta
+	for _, d := range curr {
+		p, ok := byDate[d.Date.Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		delta := DayDelta{
+			Date:       d.Date,
+			WindChange: round1(d.WindSpeedMax - p.WindSpeedMax),
+			GustChange: round1(d.WindGustMax - p.WindGustMax),
+		}
+		if p.DirUnknown || d.DirUnknown {
+			delta.DirUnknown = true
+		} else {
+			turn := Turn(p.WindDirMean, d.WindDirMean)
+			delta.PrevDir = p.WindDirMean
+			delta.CurrDir = d.WindDirMean
+			delta.DirChange = round1(turn)
+		}
+		out = append(out, delta)
+	}
+	return out
+}
+
+// Turn returns the signed shortest rotation from one direction to another in
+// degrees, in (-180, 180]: positive is clockwise (veering), negative
+// anticlockwise (backing). 350° to 10° is a 20° turn.
+func Turn(from, to float64) float64 {
+	turn := math.Mod(to-from+540, 360) - 180
+	if turn == -180 {
+		turn = 180
+	}
+	return turn
+}
@@ -0,0 +1,70 @@
+package weather
+
+import "net/http"
+
+// ErrorKind classifies a WeatherError.
+type ErrorKind int
+
+const (
+	// KindNetwork means the request never got a response (DNS, connection,
+	// timeout).
+	KindNetwork ErrorKind = iota + 1
+	// KindBadStatus means Open-Meteo answered with a non-200 status.
+	KindBadStatus
+	// KindDecode means the response could not be decoded or was malformed.
+	KindDecode
+	// KindValidation means the request was rejected before it was sent
+	// (bad days, unit or height).
+	KindValidation
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindNetwork:
+		return "network"
+	case KindBadStatus:
+		return "bad status"
+	case KindDecode:
+		return "decode"
+	case KindValidation:
+		return "validation"
+	default:
+		return "unknown"
+	}
+}
+
+// WeatherError is returned by the forecast fetches. Use errors.As to inspect
+// Kind; the underlying error is still reachable with errors.Is.
+type WeatherError struct {
+	Kind       ErrorKind
+	StatusCode int // set for KindBadStatus
+	Err        error
+}
+
+func (e *WeatherError) Error() string { return e.Err.Error() }
+
+func (e *WeatherError) Unwrap() error { return e.Err }
+
+// Temporary reports whether retrying the same request may succeed: network
+// failures, rate limiting and server errors.
+func (e *WeatherError) Temporary() bool {
+	switch e.Kind {
+	case KindNetwork:
+		return true
+	case KindBadStatus:
+		return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+	default:
+		return false
+	}
+}
+
+func validationError(err error) error {
+	return &WeatherError{Kind: KindValidation, Err: err}
+}
+
+func decodeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &WeatherError{Kind: KindDecode, Err: err}
+}
@@ -0,0 +1,149 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"time"
+)
+
+// HourlyWind is a single hourly 10m wind observation from the forecast.
+type HourlyWind struct {
+	Time      time.Time // in the location's timezone
+	Speed     float64   // in the client's WindSpeedUnit
+	Direction float64   // degrees, 0 = North
+}
+
+// HourlyWindForecaster fetches hourly wind.
+type HourlyWindForecaster interface {
+	FetchHourlyWind(ctx context.Context, days int) ([]HourlyWind, error)
+}
+
+// FetchHourlyWind retrieves hourly 10m wind speed and direction, starting
+// StartOffsetDays after today.
+func (c *OpenMeteoClient) FetchHourlyWind(ctx context.Context, days int) ([]HourlyWind, error) {
+	if days < 1 {
+		return nil, validationError(errors.New("days must be >= 1"))
+	}
+	if err := c.checkWindow(days); err != nil {
+		return nil, err
+	}
+	windUnit, err := c.windSpeedUnit()
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("hourly", "windspeed_10m,winddirection_10m")
+	query.Set("wind_speed_unit", windUnit)
+	query.Set("forecast_days", fmt.Sprintf("%d", days+c.StartOffsetDays))
+	query.Set("timezone", "auto")
+
+	var payload openMeteoResponse
+	if err := c.get(ctx, query, &payload); err != nil {
+		return nil, err
+	}
+	if payload.Hourly == nil {
+		return nil, decodeError(errors.New("open-meteo response missing hourly block"))
+	}
+
+	h := payload.Hourly
+	if len(h.Time) != len(h.WindSpeed) || len(h.Time) != len(h.WindDir) {
+		return nil, decodeError(errors.New("open-meteo hourly arrays differ in length"))
+	}
+	// Times are wall-clock times in the response's timezone; parsing them
+	// there keeps WindAt right across DST changes.
+	loc := responseLocation(payload.Timezone)
+	out := make([]HourlyWind, 0, len(h.Time))
+	var start time.Time
+	for i, ts := range h.Time {
+		t, err := time.ParseInLocation("2006-01-02T15:04", ts, loc)
+		if err != nil {
+			return nil, decodeError(fmt.Errorf("parse hour %q: %w", ts, err))
+		}
+		if i == 0 {
+			start = time.Date(t.Year(), t.Month(), t.Day()+c.StartOffsetDays, 0, 0, 0, 0, t.Location())
+		}
+		if t.Before(start) {
+			continue
+		}
+		out = append(out, HourlyWind{Time: t, Speed: h.WindSpeed[i], Direction: h.WindDir[i]})
+	}
+	return out, nil
+}
+
+// DominantDirection returns the speed-weighted vector mean of the hourly wind
+// directions in [0, 360), so light variable winds don't swamp a strong steady
+// flow. It returns 0 when there is no wind at all.
+func DominantDirection(hourly []HourlyWind) float64 {
+	var sin, cos float64
+	for _, h := range hourly {
+		r := h.Direction * math.Pi / 180
+		sin += h.Speed * math.Sin(r)
+		cos += h.Speed * math.Cos(r)
+	}
+	if sin == 0 && cos == 0 {
+		return 0
+	}
+	mean := round1(math.Atan2(sin, cos) * 180 / math.Pi)
+	return math.Mod(mean+360, 360)
+}
+
+// DirectionVariability returns the circular standard deviation of the hourly
+// directions in degrees: 0 for a perfectly steady wind, growing as directions
+// scatter. It is sqrt(-2 ln R) where R is the mean resultant length.
+func DirectionVariability(hourly []HourlyWind) float64 {
+	if len(hourly) == 0 {
+		return 0
+	}
+	var sin, cos float64
+	for _, h := range hourly {
+		r := h.Direction * math.Pi / 180
+		sin += math.Sin(r)
+		cos += math.Cos(r)
+	}
+	n := float64(len(hourly))
+	r := math.Hypot(sin/n, cos/n)
+	if r >= 1 {
+		return 0
+	}
+	if r <= 0 {
+		return math.Inf(1)
+	}
+	return round1(math.Sqrt(-2*math.Log(r)) * 180 / math.Pi)
+}
+
+// WindAt interpolates the hourly wind at t, linearly for speed and along the
+// shorter arc for direction. It returns false when t falls outside the
+// readings, which must be in time order.
+func WindAt(hourly []HourlyWind, t time.Time) (HourlyWind, bool) {
+	for i := 0; i+1 < len(hourly); i++ {
+		a, b := hourly[i], hourly[i+1]
+		if t.Before(a.Time) || !t.Before(b.Time) {
+			continue
+		}
+		f := float64(t.Sub(a.Time)) / float64(b.Time.Sub(a.Time))
+		turn := Turn(a.Direction, b.Direction)
+		return HourlyWind{
+			Time:      t,
+			Speed:     round1(a.Speed + f*(b.Speed-a.Speed)),
+			Direction: math.Mod(round1(a.Direction+f*turn)+360, 360),
+		}, true
+	}
+	if n := len(hourly); n > 0 && hourly[n-1].Time.Equal(t) {
+		return hourly[n-1], true
+	}
+	return HourlyWind{}, false
+}
+
+// GroupByDay splits hourly readings into calendar days keyed by "2006-01-02".
+func GroupByDay(hourly []HourlyWind) map[string][]HourlyWind {
+	out := make(map[string][]HourlyWind)
+	for _, h := range hourly {
+		key := h.Time.Format("2006-01-02")
+		out[key] = append(out[key], h)
+	}
+	return out
+}
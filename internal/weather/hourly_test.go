@@ -0,0 +1,100 @@
+package weather
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDominantDirection(t *testing.T) {
+	// Light, variable morning winds, then a strong afternoon easterly.
+	var day []HourlyWind
+	for i, dir := range []float64{200, 330, 10, 250, 160, 300} {
+		day = append(day, HourlyWind{Speed: 3 + float64(i%2), Direction: dir})
+	}
+	for range 6 {
+		day = append(day, HourlyWind{Speed: 35, Direction: 95})
+	}
+	if got := DominantDirection(day); math.Abs(got-95) > 10 {
+		t.Errorf("DominantDirection = %v, want about 95", got)
+	}
+
+	tests := []struct {
+		name   string
+		hourly []HourlyWind
+		want   float64
+	}{
+		{"empty", nil, 0},
+		{"across north", []HourlyWind{{Speed: 10, Direction: 350}, {Speed: 10, Direction: 10}}, 0},
+		{"weighted", []HourlyWind{{Speed: 30, Direction: 270}, {Speed: 10, Direction: 0}}, 288.4},
+	}
+	for _, tt := range tests {
+		if got := DominantDirection(tt.hourly); got != tt.want {
+			t.Errorf("%s: DominantDirection = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDirectionVariability(t *testing.T) {
+	steady := []HourlyWind{{Direction: 88}, {Direction: 90}, {Direction: 92}, {Direction: 90}}
+	scattered := []HourlyWind{{Direction: 0}, {Direction: 100}, {Direction: 200}, {Direction: 300}, {Direction: 45}}
+	if got := DirectionVariability(steady); got > 5 {
+		t.Errorf("steady: DirectionVariability = %v, want under 5", got)
+	}
+	if got := DirectionVariability(scattered); got < 90 {
+		t.Errorf("scattered: DirectionVariability = %v, want over 90", got)
+	}
+	if got := DirectionVariability(nil); got != 0 {
+		t.Errorf("empty: DirectionVariability = %v, want 0", got)
+	}
+}
+
+func TestWindAtAcrossDST(t *testing.T) {
+	// Clocks go forward at 01:00 UTC on 30 March 2025: local 00:00 and 03:00
+	// are only two hours apart.
+	c, _ := newFakeOpenMeteo(t, `{"timezone":"Europe/London","hourly":{
+		"time":["2025-03-30T00:00","2025-03-30T03:00"],
+		"windspeed_10m":[10,30],
+		"winddirection_10m":[90,90]
+	}}`)
+	hourly, err := c.FetchHourlyWind(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 3, 30, 2, 0, 0, 0, time.UTC); !hourly[1].Time.Equal(want) {
+		t.Errorf("03:00 BST read as %s, want %s", hourly[1].Time.UTC(), want)
+	}
+	// Local 02:00 is 01:00 UTC, halfway between the readings.
+	w, ok := WindAt(hourly, time.Date(2025, 3, 30, 2, 0, 0, 0, london))
+	if !ok || w.Speed != 20 {
+		t.Errorf("WindAt 02:00 BST = %v, %v; want 20", w.Speed, ok)
+	}
+}
+
+func TestUnknownTimezoneFallsBackToUTC(t *testing.T) {
+	c, _ := newFakeOpenMeteo(t, `{"timezone":"Mars/Olympus_Mons","hourly":{
+		"time":["2025-01-06T00:00","2025-01-06T01:00"],
+		"windspeed_10m":[10,30],
+		"winddirection_10m":[90,90]
+	}}`)
+	hourly, err := c.FetchHourlyWind(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("FetchHourlyWind: %v", err)
+	}
+	if loc := hourly[0].Time.Location(); loc != time.UTC {
+		t.Errorf("times in %s, want UTC", loc)
+	}
+
+	rain := rainResponse{Timezone: "Mars/Olympus_Mons"}
+	rain.Daily.Time = []string{"2025-01-06"}
+	rain.Daily.PrecipSum = []float64{0}
+	rain.Daily.PrecipProb = []int{0}
+	if _, err := rain.toRainForecasts("mm"); err != nil {
+		t.Errorf("toRainForecasts: %v", err)
+	}
+}
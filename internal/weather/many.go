@@ -0,0 +1,69 @@
+package weather
+
+import (
+	"context"
+	"sync"
+)
+
+// NamedForecaster is a forecaster for one named location.
+type NamedForecaster struct {
+	Name       string
+	Forecaster Forecaster
+}
+
+// LocationResult is the outcome of fetching one location.
+type LocationResult struct {
+	Name string
+	Days []ForecastDay
+	Err  error
+}
+
+// FetchMany fetches every location with at most concurrency requests in
+// flight (default 4) and calls each with the results in the order they
+// complete. each runs on the caller's goroutine, one result at a time. A
+// failed location is reported through LocationResult.Err and doesn't stop
+// the others. Rate limiting stays with the forecasters' Limiter: the pool
+// caps in-flight requests, the limiter caps how fast they start.
+func FetchMany(ctx context.Context, locations []NamedForecaster, days, concurrency int, each func(LocationResult)) {
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	jobs := make(chan NamedForecaster)
+	results := make(chan LocationResult)
+
+	var wg sync.WaitGroup
+	for range min(concurrency, len(locations)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for loc := range jobs {
+				fc, err := loc.Forecaster.Fetch(ctx, days)
+				results <- LocationResult{Name: loc.Name, Days: fc, Err: err}
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		for i, loc := range locations {
+			select {
+			case jobs <- loc:
+			case <-ctx.Done():
+				// Report the locations never started so none go missing.
+				for _, skipped := range locations[i:] {
+					results <- LocationResult{Name: skipped.Name, Err: ctx.Err()}
+				}
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for r := range results {
+		each(r)
+	}
+}
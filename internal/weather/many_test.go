@@ -0,0 +1,61 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingForecaster records the most fetches it saw in flight at once.
+type countingForecaster struct {
+	mu       sync.Mutex
+	inFlight int
+	max      int
+}
+
+func (f *countingForecaster) Fetch(ctx context.Context, days int) ([]ForecastDay, error) {
+	f.mu.Lock()
+	f.inFlight++
+	f.max = max(f.max, f.inFlight)
+	f.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	f.mu.Lock()
+	f.inFlight--
+	f.mu.Unlock()
+	return make([]ForecastDay, days), nil
+}
+
+type failingForecaster struct{}
+
+func (failingForecaster) Fetch(ctx context.Context, days int) ([]ForecastDay, error) {
+	return nil, errors.New("boom")
+}
+
+func TestFetchMany(t *testing.T) {
+	f := &countingForecaster{}
+	var locations []NamedForecaster
+	for i := range 6 {
+		locations = append(locations, NamedForecaster{Name: fmt.Sprint(i), Forecaster: f})
+	}
+	locations = append(locations, NamedForecaster{Name: "bad", Forecaster: failingForecaster{}})
+
+	got := map[string]LocationResult{}
+	FetchMany(context.Background(), locations, 3, 2, func(r LocationResult) {
+		got[r.Name] = r
+	})
+	if len(got) != len(locations) {
+		t.Fatalf("got %d results, want %d", len(got), len(locations))
+	}
+	if f.max > 2 {
+		t.Errorf("%d fetches in flight, want at most 2", f.max)
+	}
+	if got["bad"].Err == nil {
+		t.Error("failing location reported no error")
+	}
+	if r := got["0"]; r.Err != nil || len(r.Days) != 3 {
+		t.Errorf("location 0: %d days, error %v; want 3 days", len(r.Days), r.Err)
+	}
+}
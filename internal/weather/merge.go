@@ -0,0 +1,130 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// FetchAveraged fetches the same window from two forecasters (e.g. the
+// neighbouring Open-Meteo grid points around a site) and merges them with
+// MergeForecasts.
+func FetchAveraged(ctx context.Context, a, b Forecaster, days int) ([]ForecastDay, error) {
+	first, err := a.Fetch(ctx, days)
+	if err != nil {
+		return nil, fmt.Errorf("fetch first point: %w", err)
+	}
+	second, err := b.Fetch(ctx, days)
+	if err != nil {
+		return nil, fmt.Errorf("fetch second point: %w", err)
+	}
+	return MergeForecasts(first, second), nil
+}
+
+// MergeForecasts averages two forecasts day by day. Speeds and gusts use the
+// arithmetic mean; direction uses the circular mean so 350° and 10° give 0°,
+// and opposite directions leave it unknown. Temperatures are averaged too,
+// while the weather code and sun times, which can't be, come from the first
+// series. An optional variable missing from one series is taken from the
+// other, and stays Missing only when neither has it. Days present in only
+// one series are dropped.
+func MergeForecasts(a, b []ForecastDay) []ForecastDay {
+	byDate := make(map[string]ForecastDay, len(b))
+	for _, d := range b {
+		byDate[d.Date.Format("2006-01-02")] = d
+	}
+
+	out := make([]ForecastDay, 0, len(a))
+	for _, d := range a {
+		other, ok := byDate[d.Date.Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		merged := ForecastDay{
+			Date:         d.Date,
+			WindSpeedMax: round1((d.WindSpeedMax + other.WindSpeedMax) / 2),
+			WindGustMax:  round1((d.WindGustMax + other.WindGustMax) / 2),
+		}
+		// A direction missing from one series is taken from the other.
+		switch {
+		case d.DirUnknown && other.DirUnknown:
+			merged.DirUnknown = true
+		case d.DirUnknown:
+			merged.WindDirMean = other.WindDirMean
+		case other.DirUnknown:
+			merged.WindDirMean = d.WindDirMean
+		default:
+			merged.WindDirMean, merged.DirUnknown = meanDirection(d.WindDirMean, other.WindDirMean)
+		}
+		merged.TempMax = merged.mergeMean(VarTempMax, d, other, d.TempMax, other.TempMax)
+		merged.TempMin = merged.mergeMean(VarTempMin, d, other, d.TempMin, other.TempMin)
+		merged.WeatherCode = merged.firstAvailable(VarWeatherCode, d, other).WeatherCode
+		merged.Sunrise = merged.firstAvailable(VarSunrise, d, other).Sunrise
+		merged.Sunset = merged.firstAvailable(VarSunset, d, other).Sunset
+		out = append(out, merged)
+	}
+	return out
+}
+
+// mergeMean averages v's values x and y from days a and b, or returns the
+// one that is available. When neither is, it records v as missing and
+// returns 0.
+func (d *ForecastDay) mergeMean(v DailyVariable, a, b ForecastDay, x, y float64) float64 {
+	switch {
+	case a.Available(v) && b.Available(v):
+		return round1((x + y) / 2)
+	case a.Available(v):
+		return x
+	case b.Available(v):
+		return y
+	}
+	d.Missing = append(d.Missing, v)
+	return 0
+}
+
+// firstAvailable returns the first of days with v available. When none has
+// it, it records v as missing and returns the zero day.
+func (d *ForecastDay) firstAvailable(v DailyVariable, days ...ForecastDay) ForecastDay {
+	for _, day := range days {
+		if day.Available(v) {
+			return day
+		}
+	}
+	d.Missing = append(d.Missing, v)
+	return ForecastDay{}
+}
+
+// CircularMean returns the mean of compass directions (degrees) using the
+// vector sin/cos method, normalised to [0, 360). Directions that cancel out,
+// such as 90° and 270°, have no meaningful mean; see meanDirection.
+func CircularMean(degs ...float64) float64 {
+	mean, _ := circularMean(degs)
+	return mean
+}
+
+// minResultant is the mean resultant length below which directions are
+// treated as cancelling out, e.g. two directions more than about 174° apart.
+const minResultant = 0.05
+
+// meanDirection is CircularMean, also reporting the direction as unknown
+// when the directions cancel out.
+func meanDirection(degs ...float64) (mean float64, unknown bool) {
+	mean, resultant := circularMean(degs)
+	return mean, resultant < minResultant
+}
+
+// circularMean returns the circular mean of degs and the mean resultant
+// length, from 0 (directions cancel out) to 1 (all the same).
+func circularMean(degs []float64) (mean, resultant float64) {
+	if len(degs) == 0 {
+		return 0, 0
+	}
+	var sin, cos float64
+	for _, d := range degs {
+		r := d * math.Pi / 180
+		sin += math.Sin(r)
+		cos += math.Cos(r)
+	}
+	mean = round1(math.Atan2(sin, cos) * 180 / math.Pi)
+	return math.Mod(mean+360, 360), math.Hypot(sin, cos) / float64(len(degs))
+}
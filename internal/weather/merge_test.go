@@ -0,0 +1,111 @@
+package weather
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCircularMean(t *testing.T) {
+	tests := []struct {
+		degs []float64
+		want float64
+	}{
+		{[]float64{350, 10}, 0},
+		{[]float64{10, 350}, 0},
+		{[]float64{80, 100}, 90},
+		{[]float64{270, 300, 330}, 300},
+		{[]float64{45}, 45},
+	}
+	for _, tt := range tests {
+		if got := CircularMean(tt.degs...); got != tt.want {
+			t.Errorf("CircularMean(%v) = %v, want %v", tt.degs, got, tt.want)
+		}
+	}
+}
+
+func TestMergeForecasts(t *testing.T) {
+	day := func(d int, speed, gust, dir float64) ForecastDay {
+		return ForecastDay{Date: time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC), WindSpeedMax: speed, WindGustMax: gust, WindDirMean: dir}
+	}
+	a := []ForecastDay{day(6, 20, 30, 350), day(7, 10, 20, 90), day(8, 15, 25, 270)}
+	b := []ForecastDay{day(7, 15, 25, 100), day(8, 25, 35, 250), day(9, 30, 40, 180)}
+
+	got := MergeForecasts(a, b)
+	// The 6th and 9th are in one series only.
+	want := []ForecastDay{day(7, 12.5, 22.5, 95), day(8, 20, 30, 260)}
+	if len(got) != len(want) {
+		t.Fatalf("merged %d days, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !got[i].Date.Equal(want[i].Date) || got[i].WindSpeedMax != want[i].WindSpeedMax ||
+			got[i].WindGustMax != want[i].WindGustMax || got[i].WindDirMean != want[i].WindDirMean {
+			t.Errorf("day %d: %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// Across north the circular mean is north, not south.
+	north := MergeForecasts([]ForecastDay{day(6, 20, 30, 350)}, []ForecastDay{day(6, 20, 30, 10)})
+	if len(north) != 1 || north[0].WindDirMean != 0 {
+		t.Errorf("350° and 10° merged to %+v, want 0°", north)
+	}
+
+	// Opposite directions have no meaningful mean.
+	opposite := MergeForecasts([]ForecastDay{day(6, 20, 30, 90)}, []ForecastDay{day(6, 20, 30, 270)})
+	if len(opposite) != 1 || !opposite[0].DirUnknown {
+		t.Errorf("90° and 270° merged to %+v, want an unknown direction", opposite)
+	}
+}
+
+func TestMergeForecastsOptional(t *testing.T) {
+	date := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	sunrise := date.Add(8 * time.Hour)
+	a := ForecastDay{Date: date, TempMax: 8, TempMin: 2, WeatherCode: 3,
+		Missing: []DailyVariable{VarTempMin, VarSunrise, VarSunset}}
+	b := ForecastDay{Date: date, TempMax: 10, WeatherCode: 61, Sunrise: sunrise,
+		Missing: []DailyVariable{VarTempMin, VarSunset}}
+
+	got := MergeForecasts([]ForecastDay{a}, []ForecastDay{b})
+	if len(got) != 1 {
+		t.Fatalf("merged %d days, want 1", len(got))
+	}
+	d := got[0]
+	if d.TempMax != 9 {
+		t.Errorf("TempMax = %v, want the mean 9", d.TempMax)
+	}
+	if d.WeatherCode != 3 {
+		t.Errorf("WeatherCode = %d, want the first series' 3", d.WeatherCode)
+	}
+	if !d.Sunrise.Equal(sunrise) {
+		t.Errorf("Sunrise = %s, want the second series' %s", d.Sunrise, sunrise)
+	}
+	if want := []DailyVariable{VarTempMin, VarSunset}; !slices.Equal(d.Missing, want) {
+		t.Errorf("Missing = %v, want %v", d.Missing, want)
+	}
+
+	// One series missing a temperature doesn't halve the other's.
+	a.Missing = []DailyVariable{VarTempMax}
+	if got := MergeForecasts([]ForecastDay{a}, []ForecastDay{b}); got[0].TempMax != 10 || !got[0].Available(VarTempMax) {
+		t.Errorf("TempMax = %v (available %v), want the second series' 10", got[0].TempMax, got[0].Available(VarTempMax))
+	}
+}
+
+func TestMeanDirection(t *testing.T) {
+	tests := []struct {
+		degs    []float64
+		want    float64
+		unknown bool
+	}{
+		{[]float64{350, 10}, 0, false},
+		{[]float64{90, 270}, 0, true},
+		{[]float64{0, 180}, 0, true},
+		{[]float64{90, 250}, 170, false},
+		{nil, 0, true},
+	}
+	for _, tt := range tests {
+		got, unknown := meanDirection(tt.degs...)
+		if unknown != tt.unknown || (!unknown && got != tt.want) {
+			t.Errorf("meanDirection(%v) = %v, unknown %v; want %v, unknown %v", tt.degs, got, unknown, tt.want, tt.unknown)
+		}
+	}
+}
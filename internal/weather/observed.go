@@ -0,0 +1,32 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// maxPastDays is the furthest back Open-Meteo's past_days reaches.
+const maxPastDays = 92
+
+// FetchObserved retrieves the `days` days before today from Open-Meteo's
+// past_days, which are model analyses rather than station readings but the
+// closest thing to observations the API offers. Used to score earlier
+// forecasts. Days come back oldest first, in the same form as Fetch.
+func (c *OpenMeteoClient) FetchObserved(ctx context.Context, days int) ([]ForecastDay, error) {
+	if days < 1 || days > maxPastDays {
+		return nil, validationError(fmt.Errorf("days must be between 1 and %d", maxPastDays))
+	}
+	query := url.Values{}
+	query.Set("past_days", fmt.Sprintf("%d", days))
+	query.Set("forecast_days", "1")
+	out, err := c.fetchDaily(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, decodeError(errors.New("open-meteo returned no past days"))
+	}
+	return out[:len(out)-1], nil // drop today, still a forecast
+}
@@ -0,0 +1,102 @@
+package weather
+
+// PrecipType classifies the dominant kind of precipitation in a window.
+type PrecipType string
+
+const (
+	PrecipDry           PrecipType = "dry"
+	PrecipSteadyRain    PrecipType = "steady rain"
+	PrecipShowers       PrecipType = "showers"
+	PrecipSnow          PrecipType = "snow"
+	PrecipSleet         PrecipType = "sleet"
+	PrecipWintryShowers PrecipType = "wintry showers"
+)
+
+// Amounts below which a window counts as dry, per precipitation unit.
+const (
+	minPrecipMM   = 0.1
+	minPrecipInch = 0.004
+)
+
+// minPrecip returns the dry threshold for the forecast's unit.
+func (r RainForecast) minPrecip() float64 {
+	if r.Unit == "inch" {
+		return minPrecipInch
+	}
+	return minPrecipMM
+}
+
+// RainTiming says when in the day the precipitation falls.
+type RainTiming string
+
+const (
+	TimingDry       RainTiming = "dry"
+	TimingMorning   RainTiming = "morning"   // mostly before midday
+	TimingAfternoon RainTiming = "afternoon" // mostly 12-18
+	TimingEvening   RainTiming = "evening"   // mostly from 18
+	TimingAllDay    RainTiming = "all-day"
+	TimingScattered RainTiming = "scattered"
+)
+
+// allDayHours is how many wet hours make a day count as wet all day.
+const allDayHours = 12
+
+// Timing classifies the day's hourly precipitation. Twelve or more wet hours
+// is all day; three or more separate wet spells is scattered; otherwise the
+// day is named after the part where most of the precipitation falls.
+func (r RainForecast) Timing() RainTiming {
+	minPrecip := r.minPrecip()
+	var wet, spells int
+	var total, weighted float64
+	for h, mm := range r.HourlyMM {
+		if mm < minPrecip {
+			continue
+		}
+		wet++
+		if h == 0 || r.HourlyMM[h-1] < minPrecip {
+			spells++
+		}
+		total += mm
+		weighted += mm * (float64(h) + 0.5)
+	}
+	switch {
+	case wet == 0:
+		return TimingDry
+	case wet >= allDayHours:
+		return TimingAllDay
+	case spells >= 3:
+		return TimingScattered
+	}
+	switch center := weighted / total; {
+	case center < 12:
+		return TimingMorning
+	case center < 18:
+		return TimingAfternoon
+	default:
+		return TimingEvening
+	}
+}
+
+// MorningPrecipType classifies the morning (6am-10am) precipitation of a day.
+// Snow mixed with rain is sleet; snow mixed with showers is wintry showers.
+func (r RainForecast) MorningPrecipType() PrecipType {
+	minPrecip := r.minPrecip()
+	snow := r.SnowMM >= minPrecip
+	rain := r.RainMM >= minPrecip
+	showers := r.ShowersMM >= minPrecip
+
+	switch {
+	case snow && showers:
+		return PrecipWintryShowers
+	case snow && rain:
+		return PrecipSleet
+	case snow:
+		return PrecipSnow
+	case !rain && !showers:
+		return PrecipDry
+	case showers && r.ShowersMM >= r.RainMM:
+		return PrecipShowers
+	default:
+		return PrecipSteadyRain
+	}
+}
@@ -0,0 +1,80 @@
+package weather
+
+import (
+	"fmt"
+	"testing"
+)
+
+// morningRain is a one-day rain response with hourly rain, showers and
+// snowfall from 05:00 to 11:00.
+func morningRain() rainResponse {
+	var r rainResponse
+	r.Daily.Time = []string{"2025-01-06"}
+	r.Daily.PrecipSum = []float64{3}
+	r.Daily.PrecipProb = []int{80}
+	for h := 5; h <= 11; h++ {
+		r.Hourly.Time = append(r.Hourly.Time, fmt.Sprintf("2025-01-06T%02d:00", h))
+		r.Hourly.PrecipProb = append(r.Hourly.PrecipProb, 50)
+		r.Hourly.Precip = append(r.Hourly.Precip, 0.6)
+		r.Hourly.Rain = append(r.Hourly.Rain, 0.3)
+		r.Hourly.Showers = append(r.Hourly.Showers, 0.2)
+		r.Hourly.Snowfall = append(r.Hourly.Snowfall, 0.01)
+	}
+	return r
+}
+
+func TestMorningPrecipBreakdown(t *testing.T) {
+	r := morningRain()
+	days, err := r.toRainForecasts("mm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := days[0]
+	// 06:00 to 10:00 is five hours; snowfall comes in cm.
+	if !near(d.RainMM, 1.5) || !near(d.ShowersMM, 1) || !near(d.SnowMM, 0.5) {
+		t.Errorf("morning rain %v, showers %v, snow %v; want 1.5, 1, 0.5", d.RainMM, d.ShowersMM, d.SnowMM)
+	}
+	if got := d.MorningPrecipType(); got != PrecipWintryShowers {
+		t.Errorf("MorningPrecipType = %q, want %q", got, PrecipWintryShowers)
+	}
+}
+
+func TestHourlyArraysMustAlign(t *testing.T) {
+	for name, cut := range map[string]func(*rainHourly){
+		"probability": func(h *rainHourly) { h.PrecipProb = h.PrecipProb[1:] },
+		"precip":      func(h *rainHourly) { h.Precip = h.Precip[1:] },
+		"rain":        func(h *rainHourly) { h.Rain = h.Rain[1:] },
+		"showers":     func(h *rainHourly) { h.Showers = h.Showers[1:] },
+		"snowfall":    func(h *rainHourly) { h.Snowfall = h.Snowfall[1:] },
+		"time":        func(h *rainHourly) { h.Time = h.Time[1:] },
+	} {
+		r := morningRain()
+		cut(&r.Hourly)
+		if _, err := r.toRainForecasts("mm"); err == nil {
+			t.Errorf("%s one hour short: want an error", name)
+		}
+	}
+}
+
+func TestMorningPrecipType(t *testing.T) {
+	tests := []struct {
+		rain, showers, snow float64
+		want                PrecipType
+	}{
+		{0, 0, 0, PrecipDry},
+		{0.05, 0.05, 0, PrecipDry},
+		{2, 0.5, 0, PrecipSteadyRain},
+		{0.5, 2, 0, PrecipShowers},
+		{0, 0, 1, PrecipSnow},
+		{1, 0, 1, PrecipSleet},
+		{0, 1, 1, PrecipWintryShowers},
+	}
+	for _, tt := range tests {
+		r := RainForecast{RainMM: tt.rain, ShowersMM: tt.showers, SnowMM: tt.snow}
+		if got := r.MorningPrecipType(); got != tt.want {
+			t.Errorf("rain %v, showers %v, snow %v: %q, want %q", tt.rain, tt.showers, tt.snow, got, tt.want)
+		}
+	}
+}
+
+func near(a, b float64) bool { return a-b < 1e-9 && b-a < 1e-9 }
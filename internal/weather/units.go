@@ -0,0 +1,66 @@
+package weather
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnitSystem picks a consistent set of default units. The client's per-unit
+// fields, such as TemperatureUnit, override it.
+type UnitSystem string
+
+const (
+	// Metric uses Celsius, millimetres and km/h.
+	Metric UnitSystem = "metric"
+	// Imperial uses Fahrenheit, inches and mph.
+	Imperial UnitSystem = "imperial"
+)
+
+// ParseUnitSystem parses "metric" or "imperial", case-insensitively. An
+// empty string returns Metric.
+func ParseUnitSystem(s string) (UnitSystem, error) {
+	switch u := UnitSystem(strings.ToLower(strings.TrimSpace(s))); u {
+	case "":
+		return Metric, nil
+	case Metric, Imperial:
+		return u, nil
+	default:
+		return "", fmt.Errorf("unknown unit system %q (want metric or imperial)", s)
+	}
+}
+
+// temperatureUnit returns the system's Open-Meteo temperature unit.
+func (u UnitSystem) temperatureUnit() (string, error) {
+	switch u {
+	case "", Metric:
+		return "celsius", nil
+	case Imperial:
+		return "fahrenheit", nil
+	default:
+		return "", fmt.Errorf("unknown unit system %q (want metric or imperial)", u)
+	}
+}
+
+// precipUnit returns the system's Open-Meteo precipitation unit.
+func (u UnitSystem) precipUnit() (string, error) {
+	switch u {
+	case "", Metric:
+		return "mm", nil
+	case Imperial:
+		return "inch", nil
+	default:
+		return "", fmt.Errorf("unknown unit system %q (want metric or imperial)", u)
+	}
+}
+
+// windSpeedUnit returns the system's Open-Meteo wind speed unit.
+func (u UnitSystem) windSpeedUnit() (string, error) {
+	switch u {
+	case "", Metric:
+		return "kmh", nil
+	case Imperial:
+		return "mph", nil
+	default:
+		return "", fmt.Errorf("unknown unit system %q (want metric or imperial)", u)
+	}
+}
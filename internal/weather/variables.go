@@ -0,0 +1,79 @@
+package weather
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DailyVariable is an Open-Meteo daily variable that Fetch can request. Each
+// one populates the matching ForecastDay fields.
+type DailyVariable string
+
+const (
+	// VarWindSpeedMax fills WindSpeedMax at the client's WindHeight.
+	VarWindSpeedMax DailyVariable = "windspeed_max"
+	// VarWindGustMax fills WindGustMax.
+	VarWindGustMax DailyVariable = "windgusts_10m_max"
+	// VarWindDirDominant fills WindDirMean and DirUnknown.
+	VarWindDirDominant DailyVariable = "winddirection_10m_dominant"
+	// VarTempMax fills TempMax.
+	VarTempMax DailyVariable = "temperature_2m_max"
+	// VarTempMin fills TempMin.
+	VarTempMin DailyVariable = "temperature_2m_min"
+	// VarWeatherCode fills WeatherCode.
+	VarWeatherCode DailyVariable = "weather_code"
+	// VarSunrise fills Sunrise.
+	VarSunrise DailyVariable = "sunrise"
+	// VarSunset fills Sunset.
+	VarSunset DailyVariable = "sunset"
+)
+
+// coreDailyVariables are always requested: without them there is no wind
+// forecast.
+var coreDailyVariables = []DailyVariable{VarWindSpeedMax, VarWindGustMax, VarWindDirDominant}
+
+// DefaultDailyVariables is what Fetch requests when DailyVariables is nil.
+var DefaultDailyVariables = []DailyVariable{
+	VarWindSpeedMax, VarWindGustMax, VarWindDirDominant,
+	VarTempMax, VarTempMin, VarWeatherCode,
+}
+
+// supportedDailyVariables is every variable toForecastDays knows how to map.
+var supportedDailyVariables = append(slices.Clone(DefaultDailyVariables), VarSunrise, VarSunset)
+
+// param returns the Open-Meteo name of v for the given wind height.
+func (v DailyVariable) param(height int) string {
+	if v == VarWindSpeedMax {
+		return fmt.Sprintf("windspeed_%dm_max", height)
+	}
+	return string(v)
+}
+
+// dailyVariables returns the variables to request: the configured set (or
+// the defaults) plus the core wind variables, without duplicates.
+func (c *OpenMeteoClient) dailyVariables() ([]DailyVariable, error) {
+	vars := c.DailyVariables
+	if vars == nil {
+		vars = DefaultDailyVariables
+	}
+	out := slices.Clone(coreDailyVariables)
+	for _, v := range vars {
+		if !slices.Contains(supportedDailyVariables, v) {
+			return nil, fmt.Errorf("unsupported daily variable %q", v)
+		}
+		if !slices.Contains(out, v) {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// dailyQuery joins vars into the value of the daily query parameter.
+func dailyQuery(vars []DailyVariable, height int) string {
+	params := make([]string, len(vars))
+	for i, v := range vars {
+		params[i] = v.param(height)
+	}
+	return strings.Join(params, ",")
+}
@@ -1,13 +1,22 @@
 package weather
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/emanuelefumagalli/test-agent/internal/httpx"
 )
 
 // ForecastDay represents a daily wind forecast snapshot for a location.
@@ -15,17 +24,70 @@ type ForecastDay struct {
 	Date         time.Time
 	WindSpeedMax float64
 	WindGustMax  float64
-	WindDirMean  float64 // in degrees, 0 = North
+	WindDirMean  float64 // in degrees, 0 = North; meaningless if DirUnknown
+	TempMax      float64 // in TemperatureUnit
+	TempMin      float64
+	WeatherCode  int // WMO code, see WeatherCodeDescription
+	// DirVariability is the circular standard deviation (degrees) of the
+	// day's hourly directions; only set when hourly data was applied.
+	DirVariability float64
+	// DirUnknown is set when Open-Meteo had no direction for the day and it
+	// could not be interpolated from the neighbouring days.
+	DirUnknown bool
+	// Sunrise and Sunset are in the location's timezone, like
+	// HourlyWind.Time.
+	// They are zero unless requested with VarSunrise/VarSunset, and on days
+	// without one (polar day or night).
+	Sunrise time.Time
+	Sunset  time.Time
+	// Missing lists the requested optional variables Open-Meteo returned no
+	// value for on this day; their fields are left zero.
+	Missing []DailyVariable
+}
+
+// Available reports whether v has a value on this day. Variables that were
+// never requested also report true, their fields simply being zero.
+func (d ForecastDay) Available(v DailyVariable) bool {
+	return !slices.Contains(d.Missing, v)
 }
 
 // RainForecast represents rain data for a day with hourly detail.
 type RainForecast struct {
 	Date            time.Time
 	PrecipProb      int       // daily max precipitation probability %
-	PrecipMM        float64   // daily total precipitation mm
+	PrecipMM        float64   // daily total precipitation, in Unit
 	MorningRainProb []int     // hourly rain probability 6am-10am (indices 0-4)
-	MorningRainMM   []float64 // hourly precipitation 6am-10am
+	MorningRainMM   []float64 // hourly precipitation 6am-10am, in Unit
 	AfternoonProb   []int     // hourly rain probability 15-18 (indices 0-3)
+	// HourlyMM is the precipitation in each hour of the day (index = hour),
+	// in Unit.
+	HourlyMM [24]float64
+
+	// Morning (6am-10am) precipitation split by type, in Unit.
+	RainMM    float64 // large-scale rain
+	ShowersMM float64 // convective showers
+	SnowMM    float64 // snowfall depth (Open-Meteo's cm are stored as mm)
+
+	// Unit is the precipitation unit of the amounts above, "mm" or "inch".
+	Unit string
+}
+
+// UnitSymbol returns "mm" or "in" for labelling amounts.
+func (r RainForecast) UnitSymbol() string {
+	if r.Unit == "inch" {
+		return "in"
+	}
+	return "mm"
+}
+
+// FormatAmount formats v, in the forecast's unit, with its symbol. Inches
+// get two decimals, as typical amounts such as 0.02 in would otherwise
+// round to nothing.
+func (r RainForecast) FormatAmount(v float64) string {
+	if r.Unit == "inch" {
+		return fmt.Sprintf("%.2f in", v)
+	}
+	return fmt.Sprintf("%.1f mm", v)
 }
 
 // Forecaster fetches a set of daily wind forecasts.
@@ -43,36 +105,287 @@ type OpenMeteoClient struct {
 	Latitude   float64
 	Longitude  float64
 	HTTPClient *http.Client
+	// Timeout bounds each request made with the default client, so a stalled
+	// connection fails even without a context deadline. Ignored when
+	// HTTPClient is set. Defaults to 15s.
+	Timeout time.Duration
+	// WindHeight selects the height in metres (10, 80, 120 or 180) at which
+	// WindSpeedMax is reported. Zero means 10m.
+	WindHeight int
+	// DailyVariables selects the daily variables Fetch requests, and so the
+	// ForecastDay fields it fills. The wind speed, gust and direction are
+	// always included. Nil means DefaultDailyVariables.
+	DailyVariables []DailyVariable
+	// Units picks the default temperature, precipitation and wind speed
+	// units: Celsius, mm and km/h for Metric (the zero value), Fahrenheit,
+	// inches and mph for Imperial.
+	Units UnitSystem
+	// TemperatureUnit is "celsius" or "fahrenheit", overriding Units.
+	TemperatureUnit string
+	// PrecipUnit is "mm" or "inch", applied to rain forecasts, overriding
+	// Units.
+	PrecipUnit string
+	// WindSpeedUnit is "kmh", "mph", "ms" or "kn", applied to every wind
+	// speed and gust, overriding Units.
+	WindSpeedUnit string
+	// UserAgent is sent on every request. Defaults to httpx.DefaultUserAgent.
+	UserAgent string
+	// BaseURL overrides the forecast endpoint (tests with httptest, self-hosted
+	// instances). Defaults to the public Open-Meteo API.
+	BaseURL string
+	// APIKey authenticates against Open-Meteo's commercial tier. When set
+	// and BaseURL is empty, requests go to the customer endpoint.
+	APIKey string
+	// Limiter paces requests; share one across clients to cap the total
+	// request rate. Nil means unlimited.
+	Limiter *RateLimiter
+	// Debug, when set, receives every raw response body before decoding,
+	// after a "# <time> <lat>,<lon>" line. Each response is a
+	// single Write, so an *os.File can be shared across goroutines.
+	Debug io.Writer
+	// MaxAge is how old a response may be, judged from its Date, Age and
+	// Last-Modified headers, before get warns that a cache between us and
+	// Open-Meteo is serving a stale forecast. Defaults to 1h.
+	MaxAge time.Duration
+	// NoCache sends "Cache-Control: no-cache", asking intermediate caches to
+	// revalidate with Open-Meteo rather than answer from their copy.
+	NoCache bool
+	// StartOffsetDays starts Fetch and FetchHourlyWind that many days after
+	// today. Open-Meteo always starts at today, so the extra days are
+	// requested and dropped; offset plus days must fit in MaxForecastDays.
+	StartOffsetDays int
 }
 
-const openMeteoBaseURL = "https://api.open-meteo.com/v1/forecast"
+const (
+	openMeteoBaseURL         = "https://api.open-meteo.com/v1/forecast"
+	openMeteoCustomerBaseURL = "https://customer-api.open-meteo.com/v1/forecast"
+)
+
+const defaultWindHeight = 10
+
+const defaultTimeout = 15 * time.Second
+
+const defaultMaxAge = time.Hour
+
+// MaxForecastDays is the longest forecast Open-Meteo serves, today included.
+const MaxForecastDays = 16
+
+// windHeight returns the configured wind height, validating it against the
+// heights Open-Meteo provides.
+func (c *OpenMeteoClient) windHeight() (int, error) {
+	switch c.WindHeight {
+	case 0:
+		return defaultWindHeight, nil
+	case 10, 80, 120, 180:
+		return c.WindHeight, nil
+	default:
+		return 0, fmt.Errorf("unsupported wind height %dm (want 10, 80, 120 or 180)", c.WindHeight)
+	}
+}
+
+// temperatureUnit returns the validated Open-Meteo temperature unit.
+func (c *OpenMeteoClient) temperatureUnit() (string, error) {
+	unit := c.TemperatureUnit
+	if unit == "" {
+		return c.Units.temperatureUnit()
+	}
+	switch unit {
+	case "celsius":
+		return "celsius", nil
+	case "fahrenheit":
+		return "fahrenheit", nil
+	default:
+		return "", fmt.Errorf("unsupported temperature unit %q (want celsius or fahrenheit)", unit)
+	}
+}
 
-// Fetch retrieves up to `days` worth of daily max wind speeds and gusts.
+// precipUnit returns the validated Open-Meteo precipitation unit.
+func (c *OpenMeteoClient) precipUnit() (string, error) {
+	unit := c.PrecipUnit
+	if unit == "" {
+		return c.Units.precipUnit()
+	}
+	switch unit {
+	case "mm":
+		return "mm", nil
+	case "inch":
+		return "inch", nil
+	default:
+		return "", fmt.Errorf("unsupported precipitation unit %q (want mm or inch)", unit)
+	}
+}
+
+// windSpeedUnits maps the Open-Meteo wind speed units to their symbol and
+// size in km/h.
+var windSpeedUnits = map[string]struct {
+	symbol string
+	kmh    float64
+}{
+	"kmh": {"km/h", 1},
+	"mph": {"mph", 1.609344},
+	"ms":  {"m/s", 3.6},
+	"kn":  {"kn", 1.852},
+}
+
+// windSpeedUnit returns the validated Open-Meteo wind speed unit.
+func (c *OpenMeteoClient) windSpeedUnit() (string, error) {
+	unit := c.WindSpeedUnit
+	if unit == "" {
+		return c.Units.windSpeedUnit()
+	}
+	if _, ok := windSpeedUnits[unit]; !ok {
+		return "", fmt.Errorf("unsupported wind speed unit %q (want kmh, mph, ms or kn)", unit)
+	}
+	return unit, nil
+}
+
+// WindSpeedSymbol returns "km/h", "mph", "m/s" or "kn" for the configured
+// unit.
+func (c *OpenMeteoClient) WindSpeedSymbol() string {
+	unit, err := c.windSpeedUnit()
+	if err != nil {
+		unit = "kmh"
+	}
+	return windSpeedUnits[unit].symbol
+}
+
+// Kmh converts a speed in the configured unit to km/h, for scales defined
+// in km/h such as Beaufort's.
+func (c *OpenMeteoClient) Kmh(speed float64) float64 {
+	unit, err := c.windSpeedUnit()
+	if err != nil {
+		unit = "kmh"
+	}
+	return speed * windSpeedUnits[unit].kmh
+}
+
+// TemperatureSymbol returns "°C" or "°F" for the configured unit.
+func (c *OpenMeteoClient) TemperatureSymbol() string {
+	if unit, _ := c.temperatureUnit(); unit == "fahrenheit" {
+		return "°F"
+	}
+	return "°C"
+}
+
+// Fetch retrieves up to `days` worth of daily max wind speeds and gusts,
+// starting StartOffsetDays after today.
 func (c *OpenMeteoClient) Fetch(ctx context.Context, days int) ([]ForecastDay, error) {
 	if days < 1 {
-		return nil, errors.New("days must be >= 1")
+		return nil, validationError(errors.New("days must be >= 1"))
+	}
+	if err := c.checkWindow(days); err != nil {
+		return nil, validationError(err)
 	}
+	query := url.Values{}
+	query.Set("forecast_days", fmt.Sprintf("%d", days+c.StartOffsetDays))
+	out, err := c.fetchDaily(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return out[min(c.StartOffsetDays, len(out)):], nil
+}
+
+// checkWindow validates StartOffsetDays against a request for days.
+func (c *OpenMeteoClient) checkWindow(days int) error {
+	if c.StartOffsetDays < 0 {
+		return errors.New("start offset must be >= 0")
+	}
+	if days+c.StartOffsetDays > MaxForecastDays {
+		return fmt.Errorf("%d days from a %d-day offset exceeds the %d-day forecast", days, c.StartOffsetDays, MaxForecastDays)
+	}
+	return nil
+}
 
+// fetchDaily requests the configured daily variables over the range set in
+// query and maps them to ForecastDays.
+func (c *OpenMeteoClient) fetchDaily(ctx context.Context, query url.Values) ([]ForecastDay, error) {
+	height, err := c.windHeight()
+	if err != nil {
+		return nil, validationError(err)
+	}
+	tempUnit, err := c.temperatureUnit()
+	if err != nil {
+		return nil, validationError(err)
+	}
+	windUnit, err := c.windSpeedUnit()
+	if err != nil {
+		return nil, validationError(err)
+	}
+	vars, err := c.dailyVariables()
+	if err != nil {
+		return nil, validationError(err)
+	}
+
+	query.Set("daily", dailyQuery(vars, height))
+	query.Set("temperature_unit", tempUnit)
+	query.Set("wind_speed_unit", windUnit)
+	query.Set("timezone", "auto")
+
+	var payload openMeteoResponse
+	if err := c.get(ctx, query, &payload); err != nil {
+		return nil, err
+	}
+
+	if payload.Daily == nil {
+		return nil, decodeError(errors.New("open-meteo response missing daily block"))
+	}
+
+	out, unavailable, err := payload.Daily.toForecastDays(height, vars, responseLocation(payload.Timezone))
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	for _, v := range unavailable {
+		fmt.Printf("warning: open-meteo returned no %s for %f,%f\n", v, c.Latitude, c.Longitude)
+	}
+	return out, nil
+}
+
+// get performs a forecast request for the client's coordinates with the given
+// extra query parameters and decodes the JSON response into out.
+func (c *OpenMeteoClient) get(ctx context.Context, query url.Values, out any) error {
 	client := c.HTTPClient
 	if client == nil {
-		client = http.DefaultClient
+		timeout := c.Timeout
+		if timeout <= 0 {
+			timeout = defaultTimeout
+		}
+		client = httpx.NewClient(timeout, c.UserAgent)
+	}
+
+	if err := c.Limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("wait for rate limiter: %w", err)
 	}
 
-	query := url.Values{}
 	query.Set("latitude", fmt.Sprintf("%f", c.Latitude))
 	query.Set("longitude", fmt.Sprintf("%f", c.Longitude))
-	query.Set("daily", "windspeed_10m_max,windgusts_10m_max,winddirection_10m_dominant")
-	query.Set("forecast_days", fmt.Sprintf("%d", days))
-	query.Set("timezone", "auto")
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, openMeteoBaseURL+"?"+query.Encode(), nil)
+	baseURL := c.BaseURL
+	switch {
+	case baseURL != "":
+	case c.APIKey != "":
+		baseURL = openMeteoCustomerBaseURL
+	default:
+		baseURL = openMeteoBaseURL
+	}
+	if c.APIKey != "" {
+		query.Set("apikey", c.APIKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"?"+query.Encode(), nil)
 	if err != nil {
-		return nil, fmt.Errorf("build request: %w", err)
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent(c.UserAgent))
+	// Asking explicitly keeps responses compressed even on transports with
+	// DisableCompression, at the cost of decompressing them ourselves.
+	req.Header.Set("Accept-Encoding", "gzip")
+	if c.NoCache {
+		req.Header.Set("Cache-Control", "no-cache")
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("call open-meteo: %w", err)
+		return &WeatherError{Kind: KindNetwork, Err: fmt.Errorf("call open-meteo: %w", c.redact(err))}
 	}
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil {
@@ -81,88 +394,173 @@ func (c *OpenMeteoClient) Fetch(ctx context.Context, days int) ([]ForecastDay, e
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("open-meteo returned %s", resp.Status)
+		return &WeatherError{
+			Kind:       KindBadStatus,
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("open-meteo returned %s", resp.Status),
+		}
 	}
 
-	var payload openMeteoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return nil, fmt.Errorf("decode open-meteo response: %w", err)
+	var body io.Reader = resp.Body
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return decodeError(fmt.Errorf("open gzip open-meteo response: %w", err))
+		}
+		defer gz.Close()
+		body = gz
 	}
 
-	if payload.Daily == nil {
-		return nil, errors.New("open-meteo response missing daily block")
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return &WeatherError{Kind: KindNetwork, Err: fmt.Errorf("read open-meteo response: %w", err)}
+	}
+	if c.Debug != nil {
+		c.dumpRaw(raw)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return decodeError(fmt.Errorf("decode open-meteo response: %w", err))
+	}
+	c.logFreshness(resp.Header, raw)
+	return nil
+}
+
+// logFreshness warns when a response is older than MaxAge, and with Debug
+// set also logs how long Open-Meteo took to generate it and when. Open-Meteo
+// does not report the model run behind a forecast, so the production time is
+// the response's Last-Modified, or else its Date less any Age a cache added.
+func (c *OpenMeteoClient) logFreshness(h http.Header, raw []byte) {
+	var meta struct {
+		GenerationTimeMS float64 `json:"generationtime_ms"`
 	}
+	_ = json.Unmarshal(raw, &meta) // best effort; out was already decoded
 
-	return payload.Daily.toForecastDays()
+	produced, err := http.ParseTime(h.Get("Last-Modified"))
+	if err != nil {
+		if produced, err = http.ParseTime(h.Get("Date")); err != nil {
+			if c.Debug != nil {
+				fmt.Printf("open-meteo %f,%f: generated in %.1fms\n", c.Latitude, c.Longitude, meta.GenerationTimeMS)
+			}
+			return
+		}
+		if age, err := strconv.Atoi(h.Get("Age")); err == nil && age > 0 {
+			produced = produced.Add(-time.Duration(age) * time.Second)
+		}
+	}
+
+	maxAge := c.MaxAge
+	if maxAge <= 0 {
+		maxAge = defaultMaxAge
+	}
+	if age := time.Since(produced); age > maxAge {
+		fmt.Printf("warning: open-meteo response for %f,%f is %s old (max %s), generated in %.1fms at %s; a cache may be serving a stale forecast\n",
+			c.Latitude, c.Longitude, age.Round(time.Minute), maxAge, meta.GenerationTimeMS, produced.UTC().Format(time.RFC3339))
+	} else if c.Debug != nil {
+		fmt.Printf("open-meteo %f,%f: generated in %.1fms at %s\n",
+			c.Latitude, c.Longitude, meta.GenerationTimeMS, produced.UTC().Format(time.RFC3339))
+	}
+}
+
+// dumpRaw writes a response body to Debug. Failures are only warned about.
+func (c *OpenMeteoClient) dumpRaw(body []byte) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s %f,%f\n", time.Now().UTC().Format(time.RFC3339), c.Latitude, c.Longitude)
+	buf.Write(body)
+	buf.WriteString("\n")
+	if _, err := c.Debug.Write(buf.Bytes()); err != nil {
+		fmt.Printf("warning: write raw open-meteo response: %v\n", err)
+	}
 }
 
 type openMeteoResponse struct {
-	Daily  *openMeteoDaily  `json:"daily"`
-	Hourly *openMeteoHourly `json:"hourly"`
+	// Timezone is the zone the hourly times and sun times are given in.
+	Timezone string           `json:"timezone"`
+	Daily    *openMeteoDaily  `json:"daily"`
+	Hourly   *openMeteoHourly `json:"hourly"`
+}
+
+// responseLocation loads the timezone an Open-Meteo response's wall-clock
+// times are in. A zone that fails to load, e.g. on a host without tzdata,
+// falls back to UTC with a warning rather than failing the fetch.
+func responseLocation(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		fmt.Printf("warning: open-meteo timezone %q: %v; reading times as UTC\n", name, err)
+		return time.UTC
+	}
+	return loc
 }
 
 type openMeteoHourly struct {
-	Time        []string  `json:"time"`
-	PrecipProb  []int     `json:"precipitation_probability"`
-	Precip      []float64 `json:"precipitation"`
+	Time       []string  `json:"time"`
+	PrecipProb []int     `json:"precipitation_probability"`
+	Precip     []float64 `json:"precipitation"`
+	WindSpeed  []float64 `json:"windspeed_10m"`
+	WindDir    []float64 `json:"winddirection_10m"`
 }
 
 type openMeteoDaily struct {
-	Time         []string  `json:"time"`
-	WindSpeedMax []float64 `json:"windspeed_10m_max"`
-	WindGustMax  []float64 `json:"windgusts_10m_max"`
-	WindDirMean  []float64 `json:"winddirection_10m_dominant"`
+	Time            []string   `json:"time"`
+	WindSpeedMax    []float64  `json:"windspeed_10m_max"`
+	WindSpeed80Max  []float64  `json:"windspeed_80m_max"`
+	WindSpeed120Max []float64  `json:"windspeed_120m_max"`
+	WindSpeed180Max []float64  `json:"windspeed_180m_max"`
+	WindGustMax     []float64  `json:"windgusts_10m_max"`
+	WindDirMean     []*float64 `json:"winddirection_10m_dominant"`
+	TempMax         []*float64 `json:"temperature_2m_max"`
+	TempMin         []*float64 `json:"temperature_2m_min"`
+	WeatherCode     []*int     `json:"weather_code"`
+	Sunrise         []string   `json:"sunrise"`
+	Sunset          []string   `json:"sunset"`
+}
+
+// windSpeed returns the max wind speed series for the requested height.
+func (d *openMeteoDaily) windSpeed(height int) []float64 {
+	switch height {
+	case 80:
+		return d.WindSpeed80Max
+	case 120:
+		return d.WindSpeed120Max
+	case 180:
+		return d.WindSpeed180Max
+	default:
+		return d.WindSpeedMax
+	}
 }
 
 // FetchRain retrieves rain forecast with hourly morning data.
 func (c *OpenMeteoClient) FetchRain(ctx context.Context, days int) ([]RainForecast, error) {
 	if days < 1 {
-		return nil, errors.New("days must be >= 1")
+		return nil, validationError(errors.New("days must be >= 1"))
 	}
-
-	client := c.HTTPClient
-	if client == nil {
-		client = http.DefaultClient
+	unit, err := c.precipUnit()
+	if err != nil {
+		return nil, validationError(err)
 	}
 
 	query := url.Values{}
-	query.Set("latitude", fmt.Sprintf("%f", c.Latitude))
-	query.Set("longitude", fmt.Sprintf("%f", c.Longitude))
 	query.Set("daily", "precipitation_sum,precipitation_probability_max")
-	query.Set("hourly", "precipitation_probability,precipitation")
+	query.Set("precipitation_unit", unit)
+	query.Set("hourly", "precipitation_probability,precipitation,rain,showers,snowfall")
 	query.Set("forecast_days", fmt.Sprintf("%d", days))
 	query.Set("timezone", "Europe/London")
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, openMeteoBaseURL+"?"+query.Encode(), nil)
-	if err != nil {
-		return nil, fmt.Errorf("build request: %w", err)
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("call open-meteo: %w", err)
-	}
-	defer func() {
-		if cerr := resp.Body.Close(); cerr != nil {
-			fmt.Printf("warning: close response body: %v\n", cerr)
-		}
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("open-meteo returned %s", resp.Status)
-	}
-
 	var payload rainResponse
-	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return nil, fmt.Errorf("decode open-meteo response: %w", err)
+	if err := c.get(ctx, query, &payload); err != nil {
+		return nil, err
 	}
 
-	return payload.toRainForecasts()
+	out, err := payload.toRainForecasts(unit)
+	return out, decodeError(err)
 }
 
 type rainResponse struct {
-	Daily  rainDaily  `json:"daily"`
-	Hourly rainHourly `json:"hourly"`
+	Timezone string     `json:"timezone"`
+	Daily    rainDaily  `json:"daily"`
+	Hourly   rainHourly `json:"hourly"`
 }
 
 type rainDaily struct {
@@ -175,13 +573,38 @@ type rainHourly struct {
 	Time       []string  `json:"time"`
 	PrecipProb []int     `json:"precipitation_probability"`
 	Precip     []float64 `json:"precipitation"`
+	Rain       []float64 `json:"rain"`
+	Showers    []float64 `json:"showers"`
+	Snowfall   []float64 `json:"snowfall"`
 }
 
-func (r *rainResponse) toRainForecasts() ([]RainForecast, error) {
+func (r *rainResponse) toRainForecasts(unit string) ([]RainForecast, error) {
+	// Snowfall comes in cm when precipitation is in mm, and in inches
+	// otherwise.
+	snowFactor := 10.0
+	if unit == "inch" {
+		snowFactor = 1
+	}
+
 	if len(r.Daily.Time) == 0 {
 		return nil, errors.New("no daily rain data")
 	}
 
+	if len(r.Daily.PrecipSum) != len(r.Daily.Time) || len(r.Daily.PrecipProb) != len(r.Daily.Time) {
+		return nil, errors.New("open-meteo daily rain arrays differ in length")
+	}
+	h := r.Hourly
+	for _, arr := range []int{len(h.PrecipProb), len(h.Precip), len(h.Rain), len(h.Showers), len(h.Snowfall)} {
+		if arr != len(h.Time) {
+			return nil, errors.New("open-meteo hourly rain arrays differ in length")
+		}
+	}
+
+	// Hourly times are wall-clock times in the response's timezone. Parsing
+	// them there keeps the school-run buckets on local hours across DST
+	// changes.
+	loc := responseLocation(r.Timezone)
+
 	out := make([]RainForecast, 0, len(r.Daily.Time))
 
 	for i, dateStr := range r.Daily.Time {
@@ -194,20 +617,25 @@ func (r *rainResponse) toRainForecasts() ([]RainForecast, error) {
 			Date:       date,
 			PrecipProb: r.Daily.PrecipProb[i],
 			PrecipMM:   r.Daily.PrecipSum[i],
+			Unit:       unit,
 		}
 
 		// Extract hourly data for school times
 		for j, hourStr := range r.Hourly.Time {
-			hourTime, err := time.Parse("2006-01-02T15:04", hourStr)
+			hourTime, err := time.ParseInLocation("2006-01-02T15:04", hourStr, loc)
 			if err != nil {
 				continue
 			}
 			if hourTime.Year() == date.Year() && hourTime.Month() == date.Month() && hourTime.Day() == date.Day() {
 				hour := hourTime.Hour()
+				rf.HourlyMM[hour] += r.Hourly.Precip[j]
 				// Morning: 6am-10am for drop-off
 				if hour >= 6 && hour <= 10 {
 					rf.MorningRainProb = append(rf.MorningRainProb, r.Hourly.PrecipProb[j])
 					rf.MorningRainMM = append(rf.MorningRainMM, r.Hourly.Precip[j])
+					rf.RainMM += r.Hourly.Rain[j]
+					rf.ShowersMM += r.Hourly.Showers[j]
+					rf.SnowMM += r.Hourly.Snowfall[j] * snowFactor
 				}
 				// Afternoon: 15-18 for pickup (Wed 15-16, others 17-18)
 				if hour >= 15 && hour <= 18 {
@@ -222,26 +650,149 @@ func (r *rainResponse) toRainForecasts() ([]RainForecast, error) {
 	return out, nil
 }
 
-func (d *openMeteoDaily) toForecastDays() ([]ForecastDay, error) {
+// toForecastDays maps the daily block to ForecastDays. The core wind
+// variables are required; it also returns the requested optional variables
+// that were absent altogether. Sun times are read in loc.
+func (d *openMeteoDaily) toForecastDays(height int, vars []DailyVariable, loc *time.Location) ([]ForecastDay, []DailyVariable, error) {
 	if len(d.Time) == 0 {
-		return nil, errors.New("no daily data returned")
+		return nil, nil, errors.New("no daily data returned")
 	}
-	if len(d.Time) != len(d.WindSpeedMax) || len(d.Time) != len(d.WindGustMax) || len(d.Time) != len(d.WindDirMean) {
-		return nil, errors.New("open-meteo arrays differ in length")
+	speed := d.windSpeed(height)
+	core := []struct {
+		v   DailyVariable
+		len int
+	}{
+		{VarWindSpeedMax, len(speed)},
+		{VarWindGustMax, len(d.WindGustMax)},
+		{VarWindDirDominant, len(d.WindDirMean)},
+	}
+	for _, c := range core {
+		switch c.len {
+		case 0:
+			return nil, nil, fmt.Errorf("open-meteo response missing %s", c.v.param(height))
+		case len(d.Time):
+		default:
+			return nil, nil, errors.New("open-meteo arrays differ in length")
+		}
+	}
+
+	// Optional variables Open-Meteo can't provide for a location come back
+	// absent or as nulls. Either way the field stays zero and the day notes
+	// the variable as Missing, rather than passing the gap off as 0.
+	present := func(v DailyVariable, n int) bool {
+		return slices.Contains(vars, v) && n == len(d.Time)
+	}
+	var unavailable []DailyVariable
+	for _, o := range []struct {
+		v   DailyVariable
+		len int
+	}{
+		{VarTempMax, len(d.TempMax)},
+		{VarTempMin, len(d.TempMin)},
+		{VarWeatherCode, len(d.WeatherCode)},
+		{VarSunrise, len(d.Sunrise)},
+		{VarSunset, len(d.Sunset)},
+	} {
+		if slices.Contains(vars, o.v) && !present(o.v, o.len) {
+			unavailable = append(unavailable, o.v)
+		}
 	}
 
 	out := make([]ForecastDay, 0, len(d.Time))
 	for idx := range d.Time {
 		date, err := time.Parse("2006-01-02", d.Time[idx])
 		if err != nil {
-			return nil, fmt.Errorf("parse date %q: %w", d.Time[idx], err)
+			return nil, nil, fmt.Errorf("parse date %q: %w", d.Time[idx], err)
 		}
-		out = append(out, ForecastDay{
+		day := ForecastDay{
 			Date:         date,
-			WindSpeedMax: d.WindSpeedMax[idx],
-			WindGustMax:  d.WindGustMax[idx],
-			WindDirMean:  d.WindDirMean[idx],
-		})
+			WindSpeedMax: round1(speed[idx]),
+			WindGustMax:  round1(d.WindGustMax[idx]),
+			Missing:      slices.Clone(unavailable),
+		}
+		if dir := d.WindDirMean[idx]; dir != nil {
+			day.WindDirMean = *dir
+		} else {
+			day.DirUnknown = true
+		}
+		if present(VarTempMax, len(d.TempMax)) {
+			day.TempMax = day.optional(VarTempMax, d.TempMax[idx])
+		}
+		if present(VarTempMin, len(d.TempMin)) {
+			day.TempMin = day.optional(VarTempMin, d.TempMin[idx])
+		}
+		if present(VarWeatherCode, len(d.WeatherCode)) {
+			if code := d.WeatherCode[idx]; code != nil {
+				day.WeatherCode = *code
+			} else {
+				day.Missing = append(day.Missing, VarWeatherCode)
+			}
+		}
+		// An empty sunrise or sunset is polar day or night, not a gap.
+		if present(VarSunrise, len(d.Sunrise)) {
+			day.Sunrise = parseSunTime(d.Sunrise[idx], date, loc)
+		}
+		if present(VarSunset, len(d.Sunset)) {
+			day.Sunset = parseSunTime(d.Sunset[idx], date, loc)
+		}
+		out = append(out, day)
 	}
-	return out, nil
+	fillDirectionGaps(out)
+	return out, unavailable, nil
+}
+
+// optional returns *v rounded, or records v as missing and returns 0 for a
+// null.
+func (d *ForecastDay) optional(v DailyVariable, p *float64) float64 {
+	if p == nil {
+		d.Missing = append(d.Missing, v)
+		return 0
+	}
+	return round1(*p)
+}
+
+// parseSunTime parses an Open-Meteo sunrise or sunset in loc. Polar days come
+// back empty or pinned to another date; both yield the zero time.
+func parseSunTime(s string, date time.Time, loc *time.Location) time.Time {
+	t, err := time.ParseInLocation("2006-01-02T15:04", s, loc)
+	if err != nil || t.Format(time.DateOnly) != date.Format(time.DateOnly) {
+		return time.Time{}
+	}
+	return t
+}
+
+// fillDirectionGaps replaces a single unknown direction with the circular
+// mean of the days either side. Longer gaps, gaps at either end and gaps
+// between opposite directions stay unknown rather than being guessed.
+func fillDirectionGaps(days []ForecastDay) {
+	for i := 1; i+1 < len(days); i++ {
+		prev, next := days[i-1], days[i+1]
+		if days[i].DirUnknown && !prev.DirUnknown && !next.DirUnknown {
+			days[i].WindDirMean, days[i].DirUnknown = meanDirection(prev.WindDirMean, next.WindDirMean)
+		}
+	}
+}
+
+// redact strips the API key from the URL that net/http puts in its errors,
+// so the key never reaches the logs.
+func (c *OpenMeteoClient) redact(err error) error {
+	var uerr *url.Error
+	if c.APIKey != "" && errors.As(err, &uerr) {
+		uerr.URL = strings.ReplaceAll(uerr.URL, url.QueryEscape(c.APIKey), "REDACTED")
+	}
+	return err
+}
+
+// userAgent falls back to the shared default when ua is empty.
+func userAgent(ua string) string {
+	if ua == "" {
+		return httpx.DefaultUserAgent
+	}
+	return ua
+}
+
+// round1 rounds v to one decimal place so downstream consumers (and the LLM)
+// don't see float noise like 23.400001.
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
 }
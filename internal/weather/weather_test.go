@@ -0,0 +1,354 @@
+package weather
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeOpenMeteo serves body for every request and records the queries.
+type fakeOpenMeteo struct {
+	mu      sync.Mutex
+	queries []url.Values
+}
+
+func (f *fakeOpenMeteo) last() url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.queries) == 0 {
+		return nil
+	}
+	return f.queries[len(f.queries)-1]
+}
+
+// newFakeOpenMeteo starts a server answering body and returns a client
+// whose requests go to it.
+func newFakeOpenMeteo(t *testing.T, body string) (*OpenMeteoClient, *fakeOpenMeteo) {
+	t.Helper()
+	f := &fakeOpenMeteo{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.queries = append(f.queries, r.URL.Query())
+		f.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return &OpenMeteoClient{BaseURL: srv.URL}, f
+}
+
+const twoDays = `{"daily":{
+	"time":["2025-01-06","2025-01-07"],
+	"windspeed_10m_max":[23.400001,18.96],
+	"windgusts_10m_max":[40.04999,31],
+	"winddirection_10m_dominant":[90,270],
+	"temperature_2m_max":[8.25,9],
+	"temperature_2m_min":[2,3],
+	"weather_code":[3,61]
+}}`
+
+func TestFetchRoundsWind(t *testing.T) {
+	c, _ := newFakeOpenMeteo(t, twoDays)
+	days, err := c.Fetch(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 2 {
+		t.Fatalf("got %d days, want 2", len(days))
+	}
+	for i, want := range []struct{ speed, gust float64 }{{23.4, 40}, {19, 31}} {
+		if days[i].WindSpeedMax != want.speed || days[i].WindGustMax != want.gust {
+			t.Errorf("day %d: wind %v gusts %v, want %v gusts %v", i, days[i].WindSpeedMax, days[i].WindGustMax, want.speed, want.gust)
+		}
+	}
+}
+
+func TestFetchTemperatureUnit(t *testing.T) {
+	tests := []struct {
+		unit, want string
+	}{
+		{"", "celsius"},
+		{"celsius", "celsius"},
+		{"fahrenheit", "fahrenheit"},
+	}
+	for _, tt := range tests {
+		c, f := newFakeOpenMeteo(t, twoDays)
+		c.TemperatureUnit = tt.unit
+		if _, err := c.Fetch(context.Background(), 2); err != nil {
+			t.Fatalf("%q: %v", tt.unit, err)
+		}
+		if got := f.last().Get("temperature_unit"); got != tt.want {
+			t.Errorf("TemperatureUnit %q: temperature_unit=%q, want %q", tt.unit, got, tt.want)
+		}
+	}
+
+	c, f := newFakeOpenMeteo(t, twoDays)
+	c.TemperatureUnit = "kelvin"
+	if _, err := c.Fetch(context.Background(), 2); err == nil {
+		t.Error("TemperatureUnit kelvin: want an error")
+	}
+	if f.last() != nil {
+		t.Error("TemperatureUnit kelvin: the request was sent anyway")
+	}
+}
+
+const oneRainDay = `{"timezone":"Europe/London",
+	"daily":{"time":["2025-01-06"],"precipitation_sum":[0.02],"precipitation_probability_max":[40]},
+	"hourly":{"time":[],"precipitation_probability":[],"precipitation":[],"rain":[],"showers":[],"snowfall":[]}}`
+
+func TestFetchRainPrecipUnit(t *testing.T) {
+	tests := []struct {
+		unit, want, amount string
+	}{
+		{"", "mm", "0.0 mm"},
+		{"mm", "mm", "0.0 mm"},
+		{"inch", "inch", "0.02 in"},
+	}
+	for _, tt := range tests {
+		c, f := newFakeOpenMeteo(t, oneRainDay)
+		c.PrecipUnit = tt.unit
+		days, err := c.FetchRain(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.unit, err)
+		}
+		if got := f.last().Get("precipitation_unit"); got != tt.want {
+			t.Errorf("PrecipUnit %q: precipitation_unit=%q, want %q", tt.unit, got, tt.want)
+		}
+		if got := days[0].FormatAmount(days[0].PrecipMM); got != tt.amount {
+			t.Errorf("PrecipUnit %q: FormatAmount = %q, want %q", tt.unit, got, tt.amount)
+		}
+	}
+}
+
+func TestFetchMissingOptional(t *testing.T) {
+	c, _ := newFakeOpenMeteo(t, `{"daily":{
+		"time":["2025-01-06","2025-01-07"],
+		"windspeed_10m_max":[23,19],
+		"windgusts_10m_max":[40,31],
+		"winddirection_10m_dominant":[90,270],
+		"temperature_2m_max":[null,9],
+		"weather_code":[3,null]
+	}}`)
+	days, err := c.Fetch(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]DailyVariable{
+		{VarTempMin, VarTempMax},
+		{VarTempMin, VarWeatherCode},
+	}
+	for i, d := range days {
+		if !slices.Equal(d.Missing, want[i]) {
+			t.Errorf("day %d: Missing = %v, want %v", i, d.Missing, want[i])
+		}
+	}
+	if days[1].TempMax != 9 || days[0].WeatherCode != 3 {
+		t.Errorf("present values lost: %+v", days)
+	}
+}
+
+func TestFetchNullDirection(t *testing.T) {
+	c, _ := newFakeOpenMeteo(t, `{"daily":{
+		"time":["2025-01-06","2025-01-07","2025-01-08","2025-01-09"],
+		"windspeed_10m_max":[20,20,20,20],
+		"windgusts_10m_max":[30,30,30,30],
+		"winddirection_10m_dominant":[350,null,30,null]
+	}}`)
+	days, err := c.Fetch(context.Background(), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A single gap takes the circular mean of its neighbours, across north.
+	if days[1].DirUnknown || days[1].WindDirMean != 10 {
+		t.Errorf("gap: direction %v (unknown %v), want 10", days[1].WindDirMean, days[1].DirUnknown)
+	}
+	// A gap at the end has only one neighbour and stays unknown.
+	if !days[3].DirUnknown {
+		t.Errorf("last day: direction %v, want unknown", days[3].WindDirMean)
+	}
+	if days[0].DirUnknown || days[2].DirUnknown {
+		t.Error("days with a direction marked unknown")
+	}
+}
+
+func TestFillDirectionGapsOpposite(t *testing.T) {
+	days := []ForecastDay{{WindDirMean: 90}, {DirUnknown: true}, {WindDirMean: 270}}
+	fillDirectionGaps(days)
+	if !days[1].DirUnknown {
+		t.Errorf("gap between east and west filled with %v, want it left unknown", days[1].WindDirMean)
+	}
+}
+
+func TestFetchErrorKinds(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(down.Close)
+	empty, _ := newFakeOpenMeteo(t, `{}`)
+	badHours, _ := newFakeOpenMeteo(t, `{"hourly":{"time":["2025-01-06T00:00"],"windspeed_10m":[],"winddirection_10m":[]}}`)
+
+	tests := []struct {
+		name      string
+		fetch     func() error
+		kind      ErrorKind
+		temporary bool
+	}{
+		{"daily bad status", func() error {
+			_, err := (&OpenMeteoClient{BaseURL: down.URL}).Fetch(context.Background(), 2)
+			return err
+		}, KindBadStatus, true},
+		{"hourly days", func() error { _, err := empty.FetchHourlyWind(context.Background(), 0); return err }, KindValidation, false},
+		{"hourly missing block", func() error { _, err := empty.FetchHourlyWind(context.Background(), 1); return err }, KindDecode, false},
+		{"hourly arrays", func() error { _, err := badHours.FetchHourlyWind(context.Background(), 1); return err }, KindDecode, false},
+		{"current missing block", func() error { _, err := empty.FetchCurrent(context.Background()); return err }, KindDecode, false},
+		{"current unit", func() error {
+			c := *empty
+			c.TemperatureUnit = "kelvin"
+			_, err := c.FetchCurrent(context.Background())
+			return err
+		}, KindValidation, false},
+	}
+	for _, tt := range tests {
+		var werr *WeatherError
+		if err := tt.fetch(); !errors.As(err, &werr) {
+			t.Errorf("%s: error %v, want a WeatherError", tt.name, err)
+			continue
+		}
+		if werr.Kind != tt.kind || werr.Temporary() != tt.temporary {
+			t.Errorf("%s: kind %v (temporary %v), want %v (temporary %v)", tt.name, werr.Kind, werr.Temporary(), tt.kind, tt.temporary)
+		}
+	}
+}
+
+func TestFetchTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(5 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c := &OpenMeteoClient{BaseURL: srv.URL, Timeout: 50 * time.Millisecond}
+	start := time.Now()
+	_, err := c.Fetch(context.Background(), 2)
+	var werr *WeatherError
+	if !errors.As(err, &werr) || werr.Kind != KindNetwork {
+		t.Fatalf("Fetch error = %v, want a network WeatherError", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Fetch took %s, want it bounded by the 50ms timeout", elapsed)
+	}
+}
+
+func TestFetchGzip(t *testing.T) {
+	var gzipped bytes.Buffer
+	zw := gzip.NewWriter(&gzipped)
+	_, _ = zw.Write([]byte(twoDays))
+	_ = zw.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("Accept-Encoding %q, want gzip", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(gzipped.Bytes())
+	}))
+	t.Cleanup(srv.Close)
+
+	got, err := (&OpenMeteoClient{BaseURL: srv.URL}).Fetch(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, _ := newFakeOpenMeteo(t, twoDays)
+	want, err := plain.Fetch(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("gzip response decoded to %+v, want %+v", got, want)
+	}
+}
+
+func TestFetchWindSpeedUnit(t *testing.T) {
+	tests := []struct {
+		unit, want, symbol string
+		kmh                float64
+	}{
+		{"", "kmh", "km/h", 10},
+		{"mph", "mph", "mph", 16.09344},
+		{"ms", "ms", "m/s", 36},
+		{"kn", "kn", "kn", 18.52},
+	}
+	for _, tt := range tests {
+		c, f := newFakeOpenMeteo(t, twoDays)
+		c.WindSpeedUnit = tt.unit
+		if _, err := c.Fetch(context.Background(), 2); err != nil {
+			t.Fatalf("%q: %v", tt.unit, err)
+		}
+		if got := f.last().Get("wind_speed_unit"); got != tt.want {
+			t.Errorf("WindSpeedUnit %q: wind_speed_unit=%q, want %q", tt.unit, got, tt.want)
+		}
+		if got := c.WindSpeedSymbol(); got != tt.symbol {
+			t.Errorf("WindSpeedUnit %q: symbol %q, want %q", tt.unit, got, tt.symbol)
+		}
+		if got := c.Kmh(10); math.Abs(got-tt.kmh) > 1e-9 {
+			t.Errorf("WindSpeedUnit %q: Kmh(10) = %v, want %v", tt.unit, got, tt.kmh)
+		}
+	}
+
+	c, _ := newFakeOpenMeteo(t, twoDays)
+	c.WindSpeedUnit = "beaufort"
+	if _, err := c.Fetch(context.Background(), 2); err == nil {
+		t.Error("WindSpeedUnit beaufort: want an error")
+	}
+}
+
+func TestUnitSystemOverrides(t *testing.T) {
+	tests := []struct {
+		name                            string
+		client                          OpenMeteoClient
+		wantTemp, wantPrecip, wantSpeed string
+	}{
+		{"default", OpenMeteoClient{}, "celsius", "mm", "kmh"},
+		{"metric", OpenMeteoClient{Units: Metric}, "celsius", "mm", "kmh"},
+		{"imperial", OpenMeteoClient{Units: Imperial}, "fahrenheit", "inch", "mph"},
+		{"metric, overridden", OpenMeteoClient{Units: Metric, TemperatureUnit: "fahrenheit", PrecipUnit: "inch", WindSpeedUnit: "kn"}, "fahrenheit", "inch", "kn"},
+		{"imperial, overridden", OpenMeteoClient{Units: Imperial, TemperatureUnit: "celsius", PrecipUnit: "mm", WindSpeedUnit: "ms"}, "celsius", "mm", "ms"},
+		{"imperial, wind in km/h", OpenMeteoClient{Units: Imperial, WindSpeedUnit: "kmh"}, "fahrenheit", "inch", "kmh"},
+	}
+	for _, tt := range tests {
+		temp, err1 := tt.client.temperatureUnit()
+		precip, err2 := tt.client.precipUnit()
+		speed, err3 := tt.client.windSpeedUnit()
+		if err := errors.Join(err1, err2, err3); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if temp != tt.wantTemp || precip != tt.wantPrecip || speed != tt.wantSpeed {
+			t.Errorf("%s: units %s, %s, %s; want %s, %s, %s", tt.name, temp, precip, speed, tt.wantTemp, tt.wantPrecip, tt.wantSpeed)
+		}
+	}
+
+	c, f := newFakeOpenMeteo(t, twoDays)
+	c.Units = Imperial
+	if _, err := c.Fetch(context.Background(), 2); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.last().Get("wind_speed_unit"); got != "mph" {
+		t.Errorf("imperial: wind_speed_unit=%q, want mph", got)
+	}
+	if got := c.WindSpeedSymbol(); got != "mph" {
+		t.Errorf("imperial: symbol %q, want mph", got)
+	}
+}